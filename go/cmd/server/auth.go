@@ -0,0 +1,89 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	"github.com/einys/cmsn-scraper/internal"
+)
+
+type apiKeyCtxKey struct{}
+
+// publicPaths : 키 없이 열어 두는 경로 (모니터링용)
+var publicPaths = map[string]bool{
+	"/metrics": true,
+	"/healthz": true,
+	"/readyz":  true,
+}
+
+// requireAPIKey : API 키를 확인하고 키별 브라우저 사용 시간을 센다.
+// 스크래핑 수는 스크래핑하는 핸들러가 chargeScrapes로 센다 (작업 조회, /usage 는 세지 않음).
+// 키가 설정되어 있지 않으면 그대로 통과시킨다.
+func requireAPIKey(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if apiKeys == nil || !apiKeys.Enabled() || publicPaths[r.URL.Path] {
+			next.ServeHTTP(w, r)
+			return
+		}
+		key, ok := apiKeys.Lookup(apiKeyFrom(r))
+		if !ok {
+			writeError(w, internal.NewError(internal.CodeUnauthorized, "missing or invalid API key"))
+			return
+		}
+		meter := &internal.BrowserMeter{}
+		ctx := context.WithValue(r.Context(), apiKeyCtxKey{}, key)
+		next.ServeHTTP(w, r.WithContext(internal.WithBrowserMeter(ctx, meter)))
+		apiKeys.AddBrowserTime(key, meter.Elapsed())
+	})
+}
+
+// apiKeyFrom : auth.header 헤더 또는 Authorization: Bearer <key>
+func apiKeyFrom(r *http.Request) string {
+	if key := r.Header.Get(cfg.Auth.Header); key != "" {
+		return key
+	}
+	if token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer "); ok {
+		return strings.TrimSpace(token)
+	}
+	return ""
+}
+
+// requestKey : requireAPIKey가 확인한 키
+func requestKey(ctx context.Context) (internal.APIKey, bool) {
+	key, ok := ctx.Value(apiKeyCtxKey{}).(internal.APIKey)
+	return key, ok
+}
+
+// chargeScrapes : 요청한 키의 할당량에서 스크래핑 n건을 뺀다. 인증을 끈 경우에는 세지 않는다.
+// 요청 검사가 모두 끝난 뒤에 부른다. 잘못된 요청(400)에는 할당량을 쓰지 않는다.
+func chargeScrapes(ctx context.Context, n int) error {
+	key, ok := requestKey(ctx)
+	if !ok {
+		return nil
+	}
+	return apiKeys.Charge(key, n)
+}
+
+// refundScrapes : chargeScrapes로 센 n건을 되돌린다.
+func refundScrapes(ctx context.Context, n int) {
+	if key, ok := requestKey(ctx); ok {
+		apiKeys.Refund(key, n)
+	}
+}
+
+// usageHandler : 요청한 키의 사용량. admin 키는 모든 키의 사용량을 받는다.
+func usageHandler(w http.ResponseWriter, r *http.Request) {
+	key, ok := requestKey(r.Context())
+	if !ok {
+		writeError(w, internal.NewError(internal.CodeNotFound, "authentication is disabled"))
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	if key.Admin {
+		json.NewEncoder(w).Encode(map[string]any{"keys": apiKeys.UsageAll()})
+		return
+	}
+	json.NewEncoder(w).Encode(apiKeys.Usage(key))
+}
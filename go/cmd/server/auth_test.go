@@ -0,0 +1,159 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/einys/cmsn-scraper/internal"
+)
+
+func TestRequireAPIKey(t *testing.T) {
+	setupStubServer(t)
+	var err error
+	apiKeys, err = internal.LoadKeyStore(internal.AuthConfig{
+		Header: "X-API-Key",
+		Window: cfg.Auth.Window,
+		Keys: []internal.APIKey{
+			{Name: "frontend", Key: "secret", Requests: 2},
+			{Name: "ops", Key: "admin-secret", Admin: true},
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/scrape", scrapeHandler)
+	mux.HandleFunc("GET /usage", usageHandler)
+	mux.HandleFunc("POST /jobs", createJobHandler)
+	handler := requireAPIKey(mux)
+	jobs = internal.NewJobQueue(cfg.Jobs, runJob)
+	jobs.Close(context.Background())
+
+	do := func(path string, header http.Header) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, path, nil)
+		req.Header = header
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, req)
+		return w
+	}
+	code := func(w *httptest.ResponseRecorder) internal.ErrorCode {
+		var se internal.ScrapeError
+		json.NewDecoder(w.Body).Decode(&se)
+		return se.Code
+	}
+
+	if w := do("/scrape?url=kre.pe/abc", http.Header{}); w.Code != http.StatusUnauthorized || code(w) != internal.CodeUnauthorized {
+		t.Errorf("no key: status = %d", w.Code)
+	}
+	if w := do("/scrape?url=kre.pe/abc", http.Header{"X-Api-Key": {"wrong"}}); w.Code != http.StatusUnauthorized {
+		t.Errorf("wrong key: status = %d", w.Code)
+	}
+	if w := do("/scrape?url=kre.pe/abc", http.Header{"X-Api-Key": {"secret"}}); w.Code != http.StatusOK {
+		t.Errorf("valid key: status = %d, body = %s", w.Code, w.Body)
+	}
+	if w := do("/scrape?url=kre.pe/abc", http.Header{"Authorization": {"Bearer secret"}}); w.Code != http.StatusOK {
+		t.Errorf("bearer key: status = %d, body = %s", w.Code, w.Body)
+	}
+	if w := do("/scrape?url=kre.pe/abc", http.Header{"X-Api-Key": {"secret"}}); w.Code != http.StatusTooManyRequests || code(w) != internal.CodeQuotaExceeded {
+		t.Errorf("over quota: status = %d", w.Code)
+	}
+
+	w := do("/usage", http.Header{"X-Api-Key": {"admin-secret"}})
+	var report struct {
+		Keys []internal.KeyUsage `json:"keys"`
+	}
+	if err := json.NewDecoder(w.Body).Decode(&report); err != nil {
+		t.Fatal(err)
+	}
+	if len(report.Keys) != 2 || report.Keys[0].Name != "frontend" || report.Keys[0].Requests != 2 || report.Keys[0].RequestQuota != 2 {
+		t.Errorf("usage = %+v", report.Keys)
+	}
+}
+
+func TestQuotaChargesScrapes(t *testing.T) {
+	setupStubServer(t)
+	apiKeys, _ = internal.LoadKeyStore(internal.AuthConfig{
+		Header: "X-API-Key",
+		Window: cfg.Auth.Window,
+		Keys:   []internal.APIKey{{Name: "frontend", Key: "secret", Requests: 3}},
+	})
+	mux := http.NewServeMux()
+	mux.HandleFunc("/scrape", scrapeHandler)
+	mux.HandleFunc("/scrape/batch", batchHandler)
+	mux.HandleFunc("GET /usage", usageHandler)
+	handler := requireAPIKey(mux)
+	do := func(method, path, body string) int {
+		req := httptest.NewRequest(method, path, strings.NewReader(body))
+		req.Header.Set("X-API-Key", "secret")
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, req)
+		return w.Code
+	}
+
+	// 사용량 조회는 세지 않는다
+	for i := 0; i < 5; i++ {
+		if code := do(http.MethodGet, "/usage", ""); code != http.StatusOK {
+			t.Fatalf("usage: status = %d", code)
+		}
+	}
+	// 잘못된 요청이나 큐가 받지 않은 작업은 세지 않는다
+	if code := do(http.MethodGet, "/scrape?url=kre.pe/abc&timeout=soon", ""); code != http.StatusBadRequest {
+		t.Fatalf("bad timeout: status = %d", code)
+	}
+	if code := do(http.MethodPost, "/jobs", `{"url":"kre.pe/abc"}`); code == http.StatusAccepted {
+		t.Fatalf("closed queue accepted a job")
+	}
+	// 배치는 항목 수만큼 센다
+	if code := do(http.MethodPost, "/scrape/batch", `{"items":[{"url":"kre.pe/a"},{"url":"kre.pe/b"}]}`); code != http.StatusOK {
+		t.Fatalf("batch: status = %d", code)
+	}
+	if code := do(http.MethodPost, "/scrape/batch", `{"items":[{"url":"kre.pe/a"},{"url":"kre.pe/b"}]}`); code != http.StatusTooManyRequests {
+		t.Errorf("batch over quota: status = %d", code)
+	}
+	if code := do(http.MethodGet, "/scrape?url=kre.pe/abc", ""); code != http.StatusOK {
+		t.Errorf("last scrape: status = %d", code)
+	}
+	if code := do(http.MethodGet, "/scrape?url=kre.pe/abc", ""); code != http.StatusTooManyRequests {
+		t.Errorf("over quota: status = %d", code)
+	}
+}
+
+func TestGetJobChecksOwner(t *testing.T) {
+	setupStubServer(t)
+	apiKeys, _ = internal.LoadKeyStore(internal.AuthConfig{
+		Header: "X-API-Key",
+		Window: cfg.Auth.Window,
+		Keys: []internal.APIKey{
+			{Name: "frontend", Key: "secret"},
+			{Name: "other", Key: "other-secret"},
+			{Name: "ops", Key: "admin-secret", Admin: true},
+		},
+	})
+	jobs = internal.NewJobQueue(cfg.Jobs, runJob)
+	defer jobs.Close(context.Background())
+	job, err := jobs.Submit(internal.Job{URL: "https://kre.pe/abc", APIKey: "frontend"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /jobs/{id}", getJobHandler)
+	handler := requireAPIKey(mux)
+	for secret, want := range map[string]int{
+		"secret":       http.StatusOK,
+		"admin-secret": http.StatusOK,
+		"other-secret": http.StatusNotFound,
+	} {
+		req := httptest.NewRequest(http.MethodGet, "/jobs/"+job.ID, nil)
+		req.Header.Set("X-API-Key", secret)
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, req)
+		if w.Code != want {
+			t.Errorf("key %s: status = %d, want %d", secret, w.Code, want)
+		}
+	}
+}
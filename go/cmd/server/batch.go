@@ -0,0 +1,120 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+	"sync"
+
+	"github.com/einys/cmsn-scraper/internal"
+)
+
+// batchRequest : POST /scrape/batch 요청 본문
+//
+//	{"items": [{"url": "https://x.com/a/status/1", "type": "tweet"}, {"url": "https://kre.pe/abc"}]}
+type batchRequest struct {
+	Items  []batchItem `json:"items"`
+	Engine string      `json:"engine,omitempty"` // 모든 항목에 쓸 엔진. 비어 있으면 체인
+}
+
+type batchItem struct {
+	URL  string `json:"url"`
+	Type string `json:"type,omitempty"` // tweet | meta. 비어 있으면 추출기 목록에서 URL로 판단
+}
+
+// batchItemBytes : 항목 하나에 허용하는 본문 크기. 본문 전체는 (batch.max_items + 1) 배까지만 읽는다
+const batchItemBytes = 4 << 10
+
+// batchResult : 요청 순서대로 하나씩. 실패한 항목은 error만 채운다.
+type batchResult struct {
+	URL       string                `json:"url"`
+	Type      string                `json:"type,omitempty"`
+	Extractor string                `json:"extractor,omitempty"`
+	Engine    string                `json:"engine,omitempty"`
+	OK        bool                  `json:"ok"`
+	Data      any                   `json:"data,omitempty"`
+	Error     *internal.ScrapeError `json:"error,omitempty"`
+}
+
+func batchHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.Header().Set("Allow", http.MethodPost)
+		writeError(w, internal.NewError(internal.CodeMethodNotAllowed, "method not allowed"))
+		return
+	}
+
+	var req batchRequest
+	body := http.MaxBytesReader(w, r.Body, int64(cfg.Batch.MaxItems+1)*batchItemBytes)
+	if err := json.NewDecoder(body).Decode(&req); err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			writeError(w, internal.NewError(internal.CodeBadRequest, "request body too large (max %d bytes)", tooLarge.Limit))
+			return
+		}
+		writeError(w, internal.WrapError(internal.CodeBadRequest, err, "invalid JSON body"))
+		return
+	}
+	if len(req.Items) == 0 {
+		writeError(w, internal.NewError(internal.CodeBadRequest, "missing 'items'"))
+		return
+	}
+	if len(req.Items) > cfg.Batch.MaxItems {
+		writeError(w, internal.NewError(internal.CodeBadRequest, "too many items: %d (max %d)", len(req.Items), cfg.Batch.MaxItems))
+		return
+	}
+	ctx, cancel, err := scrapeContext(r)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	defer cancel()
+	if err := chargeScrapes(ctx, len(req.Items)); err != nil {
+		writeError(w, err)
+		return
+	}
+
+	slog.InfoContext(ctx, "📦 배치 스크래핑 요청", "items", len(req.Items))
+
+	results := make([]batchResult, len(req.Items))
+	sem := make(chan struct{}, cfg.Batch.Concurrency)
+	var wg sync.WaitGroup
+	for i, item := range req.Items {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func() {
+			defer wg.Done()
+			defer func() { <-sem }()
+			results[i] = scrapeBatchItem(ctx, req.Engine, item)
+		}()
+	}
+	wg.Wait()
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]any{"results": results})
+}
+
+// scrapeBatchItem : 항목 하나를 스크래핑한다. 에러는 결과에 담고 배치 전체를 실패시키지 않는다.
+func scrapeBatchItem(ctx context.Context, engine string, item batchItem) batchResult {
+	res := batchResult{URL: normalizeURL(item.URL), Type: item.Type}
+	url, err := targetURL(item.URL)
+	if err != nil {
+		res.Error = internal.Classify(err)
+		return res
+	}
+	ex, err := extractors.ForType(url, item.Type)
+	if err != nil {
+		res.Error = internal.Classify(err)
+		return res
+	}
+	res.Type, res.Extractor = ex.Type, ex.Name
+
+	env, err := chain.Extract(ctx, engine, url, ex)
+	if err != nil {
+		res.Error = internal.Classify(err)
+		return res
+	}
+	res.OK, res.Engine, res.Data = true, env.Engine, env.Data
+	return res
+}
@@ -0,0 +1,115 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/einys/cmsn-scraper/internal"
+)
+
+type stubScraper struct{}
+
+func (stubScraper) Name() string { return "stub" }
+func (stubScraper) ScrapeTweet(ctx context.Context, url string, opts internal.TweetOptions) (*internal.TweetData, error) {
+	return &internal.TweetData{Text: "tweet " + url}, nil
+}
+func (stubScraper) ScrapeMeta(ctx context.Context, url string, opts internal.MetaOptions) (*internal.MetaData, error) {
+	if strings.Contains(url, "broken") {
+		return nil, errors.New("boom")
+	}
+	return &internal.MetaData{Title: "meta", URL: url}, nil
+}
+
+func setupStubServer(t *testing.T) {
+	t.Helper()
+	cfg = internal.DefaultConfig()
+	registry = internal.NewRegistry()
+	registry.Register(stubScraper{})
+	apiKeys, _ = internal.LoadKeyStore(cfg.Auth)
+	readiness = &readinessCache{}
+	var err error
+	if chain, err = internal.NewChain(registry, internal.ChainPolicy{Default: []string{"stub"}}); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestBatchHandler(t *testing.T) {
+	setupStubServer(t)
+
+	body := `{"items": [
+		{"url": "https://x.com/a/status/1"},
+		{"url": "https://broken.example"},
+		{"url": "kre.pe/abc"},
+		{"url": "https://example.com", "type": "video"}
+	]}`
+	req := httptest.NewRequest(http.MethodPost, "/scrape/batch", strings.NewReader(body))
+	w := httptest.NewRecorder()
+	batchHandler(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("status = %d, body = %s", w.Code, w.Body)
+	}
+	var resp struct {
+		Results []batchResult `json:"results"`
+	}
+	if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+		t.Fatal(err)
+	}
+	if len(resp.Results) != 4 {
+		t.Fatalf("got %d results", len(resp.Results))
+	}
+
+	want := []struct {
+		url, typ string
+		ok       bool
+		code     internal.ErrorCode
+	}{
+		{"https://x.com/a/status/1", "tweet", true, ""},
+		{"https://broken.example", "meta", false, internal.CodeInternal},
+		{"https://kre.pe/abc", "meta", true, ""},
+		{"https://example.com", "video", false, internal.CodeBadRequest},
+	}
+	for i, w := range want {
+		got := resp.Results[i]
+		if got.URL != w.url || got.Type != w.typ || got.OK != w.ok {
+			t.Errorf("result[%d] = %+v, want %+v", i, got, w)
+		}
+		if !got.OK && (got.Error == nil || got.Error.Code != w.code) {
+			t.Errorf("result[%d] error = %+v, want code %s", i, got.Error, w.code)
+		}
+	}
+}
+
+func TestBatchHandlerRejectsTooManyItems(t *testing.T) {
+	setupStubServer(t)
+	cfg.Batch.MaxItems = 1
+
+	req := httptest.NewRequest(http.MethodPost, "/scrape/batch",
+		strings.NewReader(`{"items": [{"url": "a.com"}, {"url": "b.com"}]}`))
+	w := httptest.NewRecorder()
+	batchHandler(w, req)
+
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("status = %d, want 400", w.Code)
+	}
+}
+
+func TestBatchHandlerLimitsBodySize(t *testing.T) {
+	setupStubServer(t)
+	cfg.Batch.MaxItems = 1
+
+	// 항목 수를 세기 전에 본문 크기에서 막는다
+	body := `{"items": [{"url": "https://kre.pe/` + strings.Repeat("a", 3*batchItemBytes) + `"}]}`
+	req := httptest.NewRequest(http.MethodPost, "/scrape/batch", strings.NewReader(body))
+	w := httptest.NewRecorder()
+	batchHandler(w, req)
+
+	if w.Code != http.StatusBadRequest || !strings.Contains(w.Body.String(), "too large") {
+		t.Errorf("status = %d, body = %s", w.Code, w.Body)
+	}
+}
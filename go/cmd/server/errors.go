@@ -0,0 +1,38 @@
+package main
+
+import (
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/url"
+
+	"github.com/einys/cmsn-scraper/internal"
+)
+
+// writeError : 에러를 분류해서 {"code","message","retryable"} JSON으로 응답한다.
+func writeError(w http.ResponseWriter, err error) {
+	se := internal.Classify(err)
+	internal.CountRequestError(se)
+	if se.Code == internal.CodeInternal {
+		slog.Error("❌ Internal error", "err", err)
+	}
+	w.Header().Set("Content-Type", "application/json")
+	if se.Code == internal.CodeRateLimited {
+		w.Header().Set("Retry-After", "1")
+	}
+	w.WriteHeader(se.Status())
+	json.NewEncoder(w).Encode(se)
+}
+
+// targetURL : 요청의 url 값을 정규화하고 스크래핑할 수 있는 주소인지 확인한다.
+func targetURL(raw string) (string, error) {
+	target := normalizeURL(raw)
+	if target == "" {
+		return "", internal.NewError(internal.CodeBadRequest, "missing 'url'")
+	}
+	u, err := url.Parse(target)
+	if err != nil || u.Host == "" {
+		return "", internal.NewError(internal.CodeInvalidURL, "invalid 'url': %s", raw)
+	}
+	return target, nil
+}
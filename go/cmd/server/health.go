@@ -0,0 +1,130 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/einys/cmsn-scraper/internal"
+)
+
+// engineProbe : /readyz 의 엔진별 결과
+type engineProbe struct {
+	OK         bool                  `json:"ok"`
+	DurationMS int64                 `json:"duration_ms"`
+	Error      *internal.ScrapeError `json:"error,omitempty"`
+}
+
+// healthzHandler : 프로세스가 살아 있으면 200
+func healthzHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]string{"status": "ok"})
+}
+
+// readyzHandler : 브라우저 엔진마다 탭/세션을 빌려 빈 페이지를 열어 본 결과 (readiness).
+// 기본 엔진(또는 ?engine=)이 성공하면 200, 아니면 503. 결과는 엔진별로 모두 돌려준다.
+func readyzHandler(w http.ResponseWriter, r *http.Request) {
+	required := cfg.Engine
+	if name := r.URL.Query().Get("engine"); name != "" {
+		if _, ok := registry.Get(name); !ok {
+			writeError(w, internal.NewError(internal.CodeBadRequest, "unknown engine: %q", name))
+			return
+		}
+		required = name
+	}
+
+	results := readiness.get()
+
+	// 브라우저를 쓰지 않는 엔진(http)은 확인할 것이 없으니 준비된 것으로 본다
+	res, probed := results[required]
+	ready := !probed || res.OK
+
+	w.Header().Set("Content-Type", "application/json")
+	if !ready {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}
+	json.NewEncoder(w).Encode(map[string]any{
+		"ready":   ready,
+		"engine":  required,
+		"engines": results,
+	})
+}
+
+// readyProbeTTL : 확인 결과를 다시 쓰는 시간. /readyz 는 키 없이 열려 있어서
+// 요청마다 탭/세션을 빌리면 누구든 풀을 붙잡고 실제 요청과 경쟁하게 된다.
+const readyProbeTTL = 5 * time.Second
+
+// readinessCache : 엔진 확인은 한 번에 하나만 돌리고 결과를 readyProbeTTL 동안 같이 쓴다.
+type readinessCache struct {
+	mu      sync.Mutex
+	at      time.Time
+	results map[string]engineProbe
+	running chan struct{} // 진행 중인 확인. 끝나면 닫힌다
+}
+
+var readiness = &readinessCache{}
+
+// get : 최근 결과를 돌려준다. 오래됐으면 확인을 시작하고(이미 진행 중이면 그것을) 기다린다.
+func (c *readinessCache) get() map[string]engineProbe {
+	c.mu.Lock()
+	if c.results != nil && time.Since(c.at) < readyProbeTTL {
+		defer c.mu.Unlock()
+		return c.results
+	}
+	if c.running == nil {
+		c.running = make(chan struct{})
+		go c.refresh(c.running)
+	}
+	running := c.running
+	c.mu.Unlock()
+
+	<-running
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.results
+}
+
+// refresh : 요청과 상관없이 timeouts.ready 안에서 엔진을 모두 확인한다. 요청이 끊겨도 다른 요청이 결과를 기다린다.
+func (c *readinessCache) refresh(done chan struct{}) {
+	results := probeEngines()
+	c.mu.Lock()
+	c.results, c.at, c.running = results, time.Now(), nil
+	c.mu.Unlock()
+	close(done)
+}
+
+// probeEngines : 브라우저 엔진마다 탭/세션을 빌려 빈 페이지를 열어 본다.
+func probeEngines() map[string]engineProbe {
+	ctx, cancel := context.WithTimeout(context.Background(), cfg.Timeouts.Ready)
+	defer cancel()
+
+	var (
+		mu      sync.Mutex
+		wg      sync.WaitGroup
+		results = map[string]engineProbe{}
+	)
+	for _, name := range registry.Names() {
+		s, _ := registry.Get(name)
+		prober, ok := s.(internal.Prober)
+		if !ok {
+			continue
+		}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			start := time.Now()
+			err := prober.Probe(ctx)
+			res := engineProbe{OK: err == nil, DurationMS: time.Since(start).Milliseconds()}
+			if err != nil {
+				res.Error = internal.Classify(err)
+			}
+			mu.Lock()
+			results[name] = res
+			mu.Unlock()
+		}()
+	}
+	wg.Wait()
+	return results
+}
@@ -0,0 +1,106 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// probingStub : Probe 결과를 정할 수 있는 브라우저 엔진 흉내
+type probingStub struct {
+	stubScraper
+	name string
+	err  error
+}
+
+func (p probingStub) Name() string                    { return p.name }
+func (p probingStub) Probe(ctx context.Context) error { return p.err }
+
+func TestReadyzHandler(t *testing.T) {
+	setupStubServer(t)
+	registry.Register(probingStub{name: "chromedp"})
+	registry.Register(probingStub{name: "selenium", err: errors.New("chrome not reachable")})
+
+	cases := []struct {
+		query  string
+		status int
+		ready  bool
+	}{
+		{"", http.StatusOK, true}, // 기본 엔진 chromedp
+		{"?engine=selenium", http.StatusServiceUnavailable, false},
+		{"?engine=stub", http.StatusOK, true}, // 확인할 브라우저가 없는 엔진
+		{"?engine=nope", http.StatusBadRequest, false},
+	}
+	for _, c := range cases {
+		w := httptest.NewRecorder()
+		readyzHandler(w, httptest.NewRequest(http.MethodGet, "/readyz"+c.query, nil))
+		if w.Code != c.status {
+			t.Errorf("%q: status = %d, body = %s", c.query, w.Code, w.Body)
+			continue
+		}
+		if c.status == http.StatusBadRequest {
+			continue
+		}
+
+		var resp struct {
+			Ready   bool `json:"ready"`
+			Engines map[string]struct {
+				OK    bool `json:"ok"`
+				Error *struct {
+					Code string `json:"code"`
+				} `json:"error"`
+			} `json:"engines"`
+		}
+		if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+			t.Fatal(err)
+		}
+		if resp.Ready != c.ready || len(resp.Engines) != 2 || !resp.Engines["chromedp"].OK ||
+			resp.Engines["selenium"].OK || resp.Engines["selenium"].Error == nil {
+			t.Errorf("%q: resp = %+v", c.query, resp)
+		}
+	}
+}
+
+// countingProbe : Probe 호출 수를 센다
+type countingProbe struct {
+	stubScraper
+	calls *atomic.Int32
+}
+
+func (p countingProbe) Name() string { return "chromedp" }
+func (p countingProbe) Probe(ctx context.Context) error {
+	p.calls.Add(1)
+	time.Sleep(20 * time.Millisecond)
+	return nil
+}
+
+func TestReadyzSharesProbes(t *testing.T) {
+	setupStubServer(t)
+	var calls atomic.Int32
+	registry.Register(countingProbe{calls: &calls})
+
+	// 동시에 온 요청과 바로 뒤따른 요청은 확인 한 번을 같이 쓴다
+	var wg sync.WaitGroup
+	for i := 0; i < 5; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			w := httptest.NewRecorder()
+			readyzHandler(w, httptest.NewRequest(http.MethodGet, "/readyz", nil))
+			if w.Code != http.StatusOK {
+				t.Errorf("status = %d", w.Code)
+			}
+		}()
+	}
+	wg.Wait()
+	readyzHandler(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/readyz", nil))
+	if n := calls.Load(); n != 1 {
+		t.Errorf("probed %d times, want 1", n)
+	}
+}
@@ -0,0 +1,109 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/url"
+
+	"github.com/einys/cmsn-scraper/internal"
+)
+
+// jobRequest : POST /jobs 요청 본문
+//
+//	{"url": "https://x.com/a/status/1", "type": "tweet", "callback_url": "https://example.com/hook"}
+type jobRequest struct {
+	URL         string `json:"url"`
+	Type        string `json:"type,omitempty"`
+	Engine      string `json:"engine,omitempty"`
+	CallbackURL string `json:"callback_url,omitempty"`
+}
+
+// runJob : 작업 큐 워커가 호출한다. /scrape와 같은 추출기/체인을 쓴다.
+// 작업은 요청이 끝난 뒤에 돌기 때문에 브라우저 사용 시간은 여기서 요청한 키에 더한다.
+func runJob(ctx context.Context, job internal.Job) (*internal.Envelope, error) {
+	ex, err := extractors.ForType(job.URL, job.Type)
+	if err != nil {
+		return nil, err
+	}
+	if key, ok := apiKeys.Get(job.APIKey); ok {
+		meter := &internal.BrowserMeter{}
+		ctx = internal.WithBrowserMeter(ctx, meter)
+		defer func() { apiKeys.AddBrowserTime(key, meter.Elapsed()) }()
+	}
+	return chain.Extract(ctx, job.Engine, job.URL, ex)
+}
+
+func createJobHandler(w http.ResponseWriter, r *http.Request) {
+	var req jobRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, internal.WrapError(internal.CodeBadRequest, err, "invalid JSON body"))
+		return
+	}
+
+	target, err := targetURL(req.URL)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	ex, err := extractors.ForType(target, req.Type)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	if req.CallbackURL != "" {
+		u, err := url.Parse(req.CallbackURL)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Hostname() == "" {
+			writeError(w, internal.NewError(internal.CodeInvalidURL, "invalid 'callback_url'"))
+			return
+		}
+		if err := internal.CheckCallbackHost(u.Hostname()); err != nil {
+			writeError(w, err)
+			return
+		}
+	}
+
+	if err := chargeScrapes(r.Context(), 1); err != nil {
+		writeError(w, err)
+		return
+	}
+
+	submit := internal.Job{
+		URL:         target,
+		Type:        ex.Type,
+		Engine:      req.Engine,
+		CallbackURL: req.CallbackURL,
+	}
+	if key, ok := requestKey(r.Context()); ok {
+		submit.APIKey = key.Name
+	}
+	submit.RequestID = internal.RequestID(r.Context())
+	job, err := jobs.Submit(submit)
+	if err != nil {
+		// 큐가 가득 찼거나 닫혀서 받지 못한 작업은 세지 않는다
+		refundScrapes(r.Context(), 1)
+		writeError(w, err)
+		return
+	}
+
+	slog.InfoContext(r.Context(), "📮 Job queued", "job_id", job.ID, "url", job.URL)
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Location", "/jobs/"+job.ID)
+	w.WriteHeader(http.StatusAccepted)
+	json.NewEncoder(w).Encode(job)
+}
+
+// getJobHandler : 작업은 만든 키(또는 admin 키)만 볼 수 있다. 다른 키에는 없는 작업처럼 보인다.
+func getJobHandler(w http.ResponseWriter, r *http.Request) {
+	job, ok := jobs.Get(r.PathValue("id"))
+	if key, authed := requestKey(r.Context()); ok && authed && !key.Admin && job.APIKey != key.Name {
+		ok = false
+	}
+	if !ok {
+		writeError(w, internal.NewError(internal.CodeNotFound, "job not found"))
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(job)
+}
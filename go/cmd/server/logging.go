@@ -0,0 +1,61 @@
+package main
+
+import (
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/einys/cmsn-scraper/internal"
+)
+
+const requestIDHeader = "X-Request-ID"
+
+// withRequestID : 요청 ID를 받거나 새로 만들어 컨텍스트와 응답 헤더에 싣고, 요청이 끝나면 접근 로그를 남긴다.
+// 모니터링 경로(publicPaths)는 debug 레벨로만 남긴다.
+func withRequestID(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id := r.Header.Get(requestIDHeader)
+		if !validRequestID(id) {
+			id = internal.NewRequestID()
+		}
+		w.Header().Set(requestIDHeader, id)
+		ctx := internal.WithRequestID(r.Context(), id)
+
+		start := time.Now()
+		rec := &statusRecorder{ResponseWriter: w, status: http.StatusOK}
+		next.ServeHTTP(rec, r.WithContext(ctx))
+
+		level := slog.LevelInfo
+		if publicPaths[r.URL.Path] {
+			level = slog.LevelDebug
+		}
+		slog.Log(ctx, level, "📨 Request",
+			"method", r.Method, "path", r.URL.Path, "status", rec.status, "elapsed", time.Since(start))
+	})
+}
+
+// validRequestID : 클라이언트가 준 ID는 128자 이하의 출력 가능한 ASCII만 받는다.
+func validRequestID(id string) bool {
+	if id == "" || len(id) > 128 {
+		return false
+	}
+	for i := 0; i < len(id); i++ {
+		if id[i] <= ' ' || id[i] > '~' {
+			return false
+		}
+	}
+	return true
+}
+
+// statusRecorder : 접근 로그에 쓸 응답 코드
+type statusRecorder struct {
+	http.ResponseWriter
+	status int
+}
+
+func (r *statusRecorder) WriteHeader(status int) {
+	r.status = status
+	r.ResponseWriter.WriteHeader(status)
+}
+
+func (r *statusRecorder) Unwrap() http.ResponseWriter { return r.ResponseWriter }
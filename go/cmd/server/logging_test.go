@@ -0,0 +1,43 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/einys/cmsn-scraper/internal"
+)
+
+func TestWithRequestID(t *testing.T) {
+	var seen string
+	h := withRequestID(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		seen = internal.RequestID(r.Context())
+	}))
+
+	cases := []struct {
+		header string
+		keep   bool
+	}{
+		{"client-id-1", true},
+		{"", false},
+		{"has space", false},
+		{strings.Repeat("a", 129), false},
+	}
+	for _, c := range cases {
+		r := httptest.NewRequest(http.MethodGet, "/meta", nil)
+		if c.header != "" {
+			r.Header.Set("X-Request-ID", c.header)
+		}
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, r)
+
+		got := w.Header().Get("X-Request-ID")
+		if got == "" || got != seen {
+			t.Errorf("header %q: response id %q, context id %q", c.header, got, seen)
+		}
+		if (got == c.header) != c.keep {
+			t.Errorf("header %q: got id %q, keep=%v", c.header, got, c.keep)
+		}
+	}
+}
@@ -3,73 +3,220 @@ package main
 import (
 	"context"
 	"encoding/json"
-	"log"
+	"expvar"
+	"log/slog"
 	"net/http"
 	"os"
+	"os/signal"
+	"strconv"
 	"strings"
+	"syscall"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/promhttp"
 
-	"github.com/chromedp/chromedp"
 	"github.com/einys/cmsn-scraper/internal"
 )
 
 var (
-	ENGINE = "chromedp" // 기본값
+	cfg        *internal.Config
+	registry   = internal.NewRegistry()
+	chain      *internal.Chain
+	extractors = internal.DefaultExtractors()
+	jobs       *internal.JobQueue
+	apiKeys    *internal.KeyStore
 )
 
 func main() {
-	log.SetFlags(log.LstdFlags | log.Lshortfile)
+	// 설정: 기본값 → 설정 파일(-config / SCRAPER_CONFIG) → 환경변수 → 플래그
+	var err error
+	if cfg, err = internal.LoadConfig(os.Args[1:]); err != nil {
+		fatal("❌ Invalid config", err)
+	}
+	slog.SetDefault(internal.NewLogger(cfg.Log, os.Stderr))
+
+	// 트레이싱. tracing.exporter 가 none이면 span을 기록하지 않는다.
+	shutdownTracing, err := internal.SetupTracing(context.Background(), cfg.Tracing)
+	if err != nil {
+		fatal("❌ Failed to set up tracing", err)
+	}
+	if cfg.Tracing.Exporter != "none" {
+		slog.Info("🔭 Tracing enabled", "exporter", cfg.Tracing.Exporter, "endpoint", cfg.Tracing.Endpoint)
+	}
+	slog.Info("🛠️  Using SCRAPER_ENGINE", "engine", cfg.Engine)
+
+	// chromedp 탭 풀
+	tabPool := internal.NewChromedpPool(cfg)
+	if cfg.Engine == "chromedp" {
+		go func() {
+			if err := tabPool.Warm(context.Background()); err != nil {
+				slog.Warn("⚠️ Failed to warm chromedp pool", "err", err)
+			}
+		}()
+	}
+
+	// selenium 세션 풀. ChromeDriver 서비스는 프로세스당 하나만 띄운다.
+	sessionPool := internal.NewSeleniumPool(cfg)
+
+	internal.RegisterPoolGauges("chromedp", tabPool.InUse, tabPool.Open)
+	internal.RegisterPoolGauges("selenium", sessionPool.InUse, sessionPool.Open)
+
+	registry.Register(internal.NewHTTPEngine(cfg))
+	registry.Register(internal.NewChromedpEngine(tabPool, cfg))
+	registry.Register(internal.NewSeleniumEngine(sessionPool, cfg))
+
+	policy := cfg.ChainPolicy(registry.Names())
+	if chain, err = internal.NewChain(registry, policy); err != nil {
+		fatal("❌ Invalid engine chain", err)
+	}
+	slog.Info("🛠️  Engine chain", "engines", policy.Default)
+	for _, rule := range policy.Rules {
+		slog.Info("🛠️  Domain rule", "pattern", rule.Pattern, "engines", rule.Engines)
+	}
+
+	// 결과 캐시. cache.dir을 주면 재시작해도 남는다.
+	cache, err := internal.NewCache(cfg.Cache)
+	if err != nil {
+		fatal("❌ Failed to open cache", err)
+	}
+	chain.UseCache(cache)
+	chain.UseMaxDuration(cfg.Timeouts.Max)
+	chain.UseLimiter(internal.NewDomainLimiter(cfg.RateLimit))
+	if cfg.Cache.Dir != "" {
+		slog.Info("🗄️  Cache dir", "dir", cfg.Cache.Dir)
+	}
 
-	// 환경변수로 엔진 설정
-	ENGINE = os.Getenv("SCRAPER_ENGINE")
-	log.Println("🛠️  Using SCRAPER_ENGINE:", ENGINE)
+	// 비동기 작업 큐. 작업은 요청이 끝나도 백그라운드에서 계속 돈다.
+	jobs = internal.NewJobQueue(cfg.Jobs, runJob)
+
+	// API 키. 설정된 키가 없으면 인증 없이 연다.
+	if apiKeys, err = internal.LoadKeyStore(cfg.Auth); err != nil {
+		fatal("❌ Failed to load API keys", err)
+	}
+	if apiKeys.Enabled() {
+		slog.Info("🔑 API key auth enabled", "header", cfg.Auth.Header)
+	} else {
+		slog.Warn("⚠️ No API keys configured, authentication disabled")
+	}
 
 	// 서버 시작
-	http.HandleFunc("/scrape-twitter", tweetHandler)
-	http.HandleFunc("/meta", metaHandler)
-	log.Println("🚀 Server running on http://localhost:18081")
-	log.Fatal(http.ListenAndServe(":18081", nil))
+	mux := http.NewServeMux()
+	mux.HandleFunc("/scrape-twitter", tweetHandler)
+	mux.HandleFunc("/meta", metaHandler)
+	mux.HandleFunc("/scrape", scrapeHandler)
+	mux.HandleFunc("/scrape/batch", batchHandler)
+	mux.HandleFunc("POST /jobs", createJobHandler)
+	mux.HandleFunc("GET /jobs/{id}", getJobHandler)
+	mux.HandleFunc("GET /usage", usageHandler)
+	mux.Handle("GET /debug/vars", expvar.Handler())
+	mux.Handle("GET /metrics", promhttp.Handler())
+	mux.HandleFunc("GET /healthz", healthzHandler)
+	mux.HandleFunc("GET /readyz", readyzHandler)
+	srv := &http.Server{Addr: cfg.Addr, Handler: withRequestID(traceRequest(requireAPIKey(mux)))}
 
-}
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
 
-func tweetHandler(w http.ResponseWriter, r *http.Request) {
-	url := r.URL.Query().Get("url")
-	if url == "" {
-		http.Error(w, "Missing 'url'", http.StatusBadRequest)
-		return
+	// 디스크 캐시에서 만료된 파일 정리
+	go cache.Run(ctx)
+
+	serveErr := make(chan error, 1)
+	go func() {
+		slog.Info("🚀 Server running", "addr", cfg.Addr)
+		serveErr <- srv.ListenAndServe()
+	}()
+
+	exitCode := 0
+	select {
+	case err := <-serveErr:
+		slog.Error("❌ Server stopped", "err", err)
+		exitCode = 1
+	case <-ctx.Done():
+		slog.Info("🛑 Shutting down, waiting for in-flight requests", "timeout", cfg.Timeouts.Shutdown)
+	}
+
+	// 새 요청은 받지 않고 처리 중인 스크래핑과 남은 작업이 끝나기를 기다린다.
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), cfg.Timeouts.Shutdown)
+	if err := srv.Shutdown(shutdownCtx); err != nil {
+		slog.Warn("⚠️ Shutdown timed out, aborting remaining requests", "err", err)
+	}
+	if err := jobs.Close(shutdownCtx); err != nil {
+		slog.Warn("⚠️ Shutdown timed out, aborting remaining jobs", "err", err)
+	}
+	if err := shutdownTracing(shutdownCtx); err != nil {
+		slog.Warn("⚠️ Failed to flush traces", "err", err)
 	}
+	cancel()
 
-	log.Println("🐦 트윗 스크래핑 요청 URL:", url)
+	// 남은 탭/세션/브라우저/ChromeDriver 정리
+	tabPool.Close()
+	sessionPool.Close()
+	slog.Info("👋 Bye")
+	os.Exit(exitCode)
+}
 
-	if ENGINE == "chromedp" {
-		ctx, cancel := chromedp.NewContext(context.Background())
-		defer cancel()
+// fatal : 시작할 수 없는 설정 오류
+func fatal(msg string, err error) {
+	slog.Error(msg, "err", err)
+	os.Exit(1)
+}
 
-		data, err := internal.ScrapeTweetChromedp(ctx, url)
-		if err != nil {
-			http.Error(w, err.Error(), 500)
-			return
-		}
-		json.NewEncoder(w).Encode(data)
+func tweetHandler(w http.ResponseWriter, r *http.Request) {
+	url, err := targetURL(r.URL.Query().Get("url"))
+	if err != nil {
+		writeError(w, err)
 		return
 	}
-
-	// 기본: selenium
-	wd, quit, err := internal.InitWebDriver()
+	opts, err := tweetOptions(r)
 	if err != nil {
-		http.Error(w, err.Error(), 500)
+		writeError(w, err)
+		return
+	}
+	ctx, cancel, err := scrapeContext(r)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	defer cancel()
+	if err := chargeScrapes(ctx, 1); err != nil {
+		writeError(w, err)
 		return
 	}
-	defer quit()
-	defer wd.Quit()
 
-	data, err := internal.ScrapeTweet(wd, url)
+	slog.InfoContext(ctx, "🐦 트윗 스크래핑 요청", "url", url)
+
+	data, err := chain.ScrapeTweet(ctx, r.URL.Query().Get("engine"), url, opts)
 	if err != nil {
-		http.Error(w, err.Error(), 500)
+		writeError(w, err)
 		return
 	}
+	w.Header().Set("X-Scraper-Engine", data.Engine)
+	setCacheHeader(w, data.CacheInfo)
 	json.NewEncoder(w).Encode(data)
 }
 
+// tweetOptions : ?thread=1 이면 작성자 답글 타래도 모은다. ?depth= 로 개수를 정하고 thread.max_depth 를 넘지 않는다.
+func tweetOptions(r *http.Request) (internal.TweetOptions, error) {
+	q := r.URL.Query()
+	if q.Get("thread") == "" {
+		return internal.TweetOptions{}, nil
+	}
+	on, err := strconv.ParseBool(q.Get("thread"))
+	if err != nil {
+		return internal.TweetOptions{}, internal.NewError(internal.CodeBadRequest, "invalid 'thread': %s", q.Get("thread"))
+	}
+	if !on {
+		return internal.TweetOptions{}, nil
+	}
+	depth := cfg.Thread.Depth
+	if raw := q.Get("depth"); raw != "" {
+		if depth, err = strconv.Atoi(raw); err != nil || depth <= 0 {
+			return internal.TweetOptions{}, internal.NewError(internal.CodeBadRequest, "invalid 'depth': %s", raw)
+		}
+	}
+	return internal.TweetOptions{Thread: min(depth, cfg.Thread.MaxDepth)}, nil
+}
+
 func normalizeURL(u string) string {
 	u = strings.TrimSpace(u)
 	if u == "" {
@@ -81,47 +228,69 @@ func normalizeURL(u string) string {
 	return u
 }
 
-func metaHandler(w http.ResponseWriter, r *http.Request) {
-	url := r.URL.Query().Get("url")
-	if url == "" {
-		http.Error(w, "Missing 'url'", http.StatusBadRequest)
-		return
+// scrapeContext : 클라이언트가 끊으면 같이 끝나는 요청 컨텍스트.
+// ?timeout=20s (또는 초 단위 숫자) 를 주면 timeouts.max 를 넘지 않는 선에서 마감 시간을 건다.
+// Cache-Control: no-cache 요청은 캐시를 건너뛰고 새로 스크래핑한다.
+func scrapeContext(r *http.Request) (context.Context, context.CancelFunc, error) {
+	parent := r.Context()
+	if strings.Contains(strings.ToLower(r.Header.Get("Cache-Control")), "no-cache") {
+		parent = internal.WithNoCache(parent)
 	}
-
-	url = normalizeURL(url)
-	if url == "" {
-		http.Error(w, "Invalid 'url'", http.StatusBadRequest)
-		return
+	raw := r.URL.Query().Get("timeout")
+	if raw == "" {
+		ctx, cancel := context.WithCancel(parent)
+		return ctx, cancel, nil
 	}
-
-	log.Println("🌐 메타데이터 스크래핑 요청 URL:", url)
-
-	if ENGINE == "chromedp" {
-		ctx, cancel := chromedp.NewContext(context.Background())
-		defer cancel()
-
-		data, err := internal.ScrapeMetaChromedp(ctx, url)
-		if err != nil {
-			http.Error(w, err.Error(), 500)
-			return
+	d, err := time.ParseDuration(raw)
+	if err != nil {
+		secs, convErr := strconv.Atoi(raw)
+		if convErr != nil {
+			return nil, nil, internal.NewError(internal.CodeBadRequest, "invalid 'timeout': %s", raw)
 		}
-		json.NewEncoder(w).Encode(data)
-		return
+		d = time.Duration(secs) * time.Second
 	}
+	if d <= 0 {
+		return nil, nil, internal.NewError(internal.CodeBadRequest, "invalid 'timeout': %s", raw)
+	}
+	ctx, cancel := context.WithTimeout(parent, min(d, cfg.Timeouts.Max))
+	return ctx, cancel, nil
+}
 
-	// 기본: selenium
-	wd, quit, err := internal.InitWebDriver()
+func metaHandler(w http.ResponseWriter, r *http.Request) {
+	url, err := targetURL(r.URL.Query().Get("url"))
 	if err != nil {
-		http.Error(w, err.Error(), 500)
+		writeError(w, err)
+		return
+	}
+	ctx, cancel, err := scrapeContext(r)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	defer cancel()
+	if err := chargeScrapes(ctx, 1); err != nil {
+		writeError(w, err)
 		return
 	}
-	defer quit()
-	defer wd.Quit()
 
-	data, err := internal.ScrapeMeta(wd, url)
+	slog.InfoContext(ctx, "🌐 메타데이터 스크래핑 요청", "url", url)
+
+	opts := extractors.Lookup(url).Meta
+	data, err := chain.ScrapeMeta(ctx, r.URL.Query().Get("engine"), url, opts)
 	if err != nil {
-		http.Error(w, err.Error(), 500)
+		writeError(w, err)
 		return
 	}
+	w.Header().Set("X-Scraper-Engine", data.Engine)
+	setCacheHeader(w, data.CacheInfo)
 	json.NewEncoder(w).Encode(data)
 }
+
+// setCacheHeader : X-Cache: HIT | MISS
+func setCacheHeader(w http.ResponseWriter, info internal.CacheInfo) {
+	if info.CacheHit {
+		w.Header().Set("X-Cache", "HIT")
+	} else {
+		w.Header().Set("X-Cache", "MISS")
+	}
+}
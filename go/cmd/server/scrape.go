@@ -0,0 +1,45 @@
+package main
+
+import (
+	"encoding/json"
+	"log/slog"
+	"net/http"
+)
+
+// scrapeHandler : /scrape?url= 하나로 트윗/일반 페이지를 모두 처리한다.
+// 호스트에 맞는 추출기를 골라서 internal.Envelope로 감싸 돌려준다. ?type=tweet|meta 로 강제할 수 있다.
+func scrapeHandler(w http.ResponseWriter, r *http.Request) {
+	url, err := targetURL(r.URL.Query().Get("url"))
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	ex, err := extractors.ForType(url, r.URL.Query().Get("type"))
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	ctx, cancel, err := scrapeContext(r)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	defer cancel()
+	if err := chargeScrapes(ctx, 1); err != nil {
+		writeError(w, err)
+		return
+	}
+
+	slog.InfoContext(ctx, "🔎 스크래핑 요청", "url", url, "extractor", ex.Name)
+
+	env, err := chain.Extract(ctx, r.URL.Query().Get("engine"), url, ex)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("X-Scraper-Engine", env.Engine)
+	setCacheHeader(w, env.CacheInfo)
+	json.NewEncoder(w).Encode(env)
+}
@@ -0,0 +1,137 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/einys/cmsn-scraper/internal"
+)
+
+func TestScrapeHandlerEnvelope(t *testing.T) {
+	setupStubServer(t)
+
+	cases := []struct {
+		url, typ, extractor string
+	}{
+		{"https://x.com/a/status/1", "tweet", "tweet"},
+		{"https://foo.notion.site/page", "meta", "notion"},
+		{"kre.pe/abc", "meta", "krepe"},
+	}
+	for _, c := range cases {
+		req := httptest.NewRequest(http.MethodGet, "/scrape?url="+c.url, nil)
+		w := httptest.NewRecorder()
+		scrapeHandler(w, req)
+
+		if w.Code != http.StatusOK {
+			t.Fatalf("%s: status = %d, body = %s", c.url, w.Code, w.Body)
+		}
+		var env struct {
+			Type      string          `json:"type"`
+			Extractor string          `json:"extractor"`
+			Engine    string          `json:"engine"`
+			Data      json.RawMessage `json:"data"`
+		}
+		if err := json.NewDecoder(w.Body).Decode(&env); err != nil {
+			t.Fatal(err)
+		}
+		if env.Type != c.typ || env.Extractor != c.extractor || env.Engine != "stub" || len(env.Data) == 0 {
+			t.Errorf("%s: envelope = %+v", c.url, env)
+		}
+	}
+}
+
+func TestScrapeHandlerErrors(t *testing.T) {
+	setupStubServer(t)
+
+	cases := []struct {
+		query  string
+		status int
+		code   internal.ErrorCode
+	}{
+		{"", http.StatusBadRequest, internal.CodeBadRequest},
+		{"url=https://%5B::1", http.StatusBadRequest, internal.CodeInvalidURL},
+		{"url=example.com&type=video", http.StatusBadRequest, internal.CodeBadRequest},
+		{"url=example.com&engine=nope", http.StatusBadRequest, internal.CodeBadRequest},
+		{"url=broken.example", http.StatusInternalServerError, internal.CodeInternal},
+	}
+	for _, c := range cases {
+		req := httptest.NewRequest(http.MethodGet, "/scrape?"+c.query, nil)
+		w := httptest.NewRecorder()
+		scrapeHandler(w, req)
+
+		var se internal.ScrapeError
+		if err := json.NewDecoder(w.Body).Decode(&se); err != nil {
+			t.Fatalf("%s: %v", c.query, err)
+		}
+		if w.Code != c.status || se.Code != c.code || se.Message == "" {
+			t.Errorf("%s: status = %d, error = %+v, want %d %s", c.query, w.Code, se, c.status, c.code)
+		}
+	}
+}
+
+func TestScrapeContextTimeout(t *testing.T) {
+	setupStubServer(t)
+	cfg.Timeouts.Max = 30 * time.Second
+
+	cases := []struct {
+		timeout string
+		want    time.Duration // 0이면 마감 시간 없음
+		bad     bool
+	}{
+		{"", 0, false},
+		{"5s", 5 * time.Second, false},
+		{"3", 3 * time.Second, false},
+		{"10m", 30 * time.Second, false},
+		{"soon", 0, true},
+		{"-1s", 0, true},
+	}
+	for _, c := range cases {
+		req := httptest.NewRequest(http.MethodGet, "/scrape?url=a.com&timeout="+c.timeout, nil)
+		ctx, cancel, err := scrapeContext(req)
+		if c.bad {
+			if err == nil {
+				t.Errorf("%q: expected error", c.timeout)
+			}
+			continue
+		}
+		if err != nil {
+			t.Fatalf("%q: %v", c.timeout, err)
+		}
+		deadline, ok := ctx.Deadline()
+		switch {
+		case c.want == 0 && ok:
+			t.Errorf("%q: unexpected deadline", c.timeout)
+		case c.want > 0 && (!ok || time.Until(deadline) > c.want || time.Until(deadline) < c.want-time.Second):
+			t.Errorf("%q: deadline in %v, want %v", c.timeout, time.Until(deadline), c.want)
+		}
+		cancel()
+	}
+}
+
+func TestTweetOptions(t *testing.T) {
+	setupStubServer(t)
+	cfg.Thread = internal.ThreadConfig{Depth: 10, MaxDepth: 25}
+
+	cases := []struct {
+		query  string
+		thread int
+		ok     bool
+	}{
+		{"", 0, true},
+		{"?thread=0", 0, true},
+		{"?thread=1", 10, true},
+		{"?thread=true&depth=3", 3, true},
+		{"?thread=1&depth=100", 25, true},
+		{"?thread=1&depth=0", 0, false},
+		{"?thread=maybe", 0, false},
+	}
+	for _, c := range cases {
+		opts, err := tweetOptions(httptest.NewRequest(http.MethodGet, "/scrape-twitter"+c.query, nil))
+		if (err == nil) != c.ok || opts.Thread != c.thread {
+			t.Errorf("%s: opts = %+v, err = %v", c.query, opts, err)
+		}
+	}
+}
@@ -0,0 +1,66 @@
+package main
+
+import (
+	"net/http"
+	"strings"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/einys/cmsn-scraper/internal"
+)
+
+// traceRequest : 요청마다 서버 span을 연다. traceparent 헤더가 있으면 호출한 쪽 트레이스에 이어 붙인다.
+func traceRequest(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx := otel.GetTextMapPropagator().Extract(r.Context(), propagation.HeaderCarrier(r.Header))
+		ctx, span := internal.Tracer().Start(ctx, r.Method+" "+routeName(r.URL.Path),
+			trace.WithSpanKind(trace.SpanKindServer),
+			trace.WithAttributes(
+				attribute.String("http.request.method", r.Method),
+				attribute.String("url.path", r.URL.Path),
+				attribute.String("request.id", internal.RequestID(ctx)),
+			),
+		)
+		defer span.End()
+
+		rec := &statusRecorder{ResponseWriter: w, status: http.StatusOK}
+		next.ServeHTTP(rec, r.WithContext(ctx))
+
+		span.SetAttributes(attribute.Int("http.response.status_code", rec.status))
+		if rec.status >= http.StatusInternalServerError {
+			span.SetStatus(codes.Error, http.StatusText(rec.status))
+		}
+	})
+}
+
+// knownRoutes : main에서 등록한 경로. 나머지 경로는 span 이름을 하나로 묶는다
+var knownRoutes = map[string]bool{
+	"/scrape-twitter": true,
+	"/meta":           true,
+	"/scrape":         true,
+	"/scrape/batch":   true,
+	"/jobs":           true,
+	"/usage":          true,
+	"/debug/vars":     true,
+	"/metrics":        true,
+	"/healthz":        true,
+	"/readyz":         true,
+}
+
+// unmatchedRoute : 등록되지 않은 경로의 span 이름. 클라이언트가 보낸 경로를 그대로 쓰면 이름이 끝없이 늘어난다
+const unmatchedRoute = "unmatched"
+
+// routeName : span 이름에 쓸 경로. 작업 ID처럼 요청마다 다른 부분은 뺀다.
+func routeName(path string) string {
+	switch {
+	case strings.HasPrefix(path, "/jobs/"):
+		return "/jobs/{id}"
+	case knownRoutes[path]:
+		return path
+	}
+	return unmatchedRoute
+}
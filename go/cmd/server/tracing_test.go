@@ -0,0 +1,66 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func TestTraceRequest(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	prevProvider, prevPropagator := otel.GetTracerProvider(), otel.GetTextMapPropagator()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+	t.Cleanup(func() {
+		otel.SetTracerProvider(prevProvider)
+		otel.SetTextMapPropagator(prevPropagator)
+	})
+
+	var inner trace.SpanContext
+	h := traceRequest(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		inner = trace.SpanFromContext(r.Context()).SpanContext()
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	r := httptest.NewRequest(http.MethodGet, "/jobs/abc", nil)
+	r.Header.Set("traceparent", "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+	h.ServeHTTP(httptest.NewRecorder(), r)
+
+	spans := recorder.Ended()
+	if len(spans) != 1 {
+		t.Fatalf("got %d spans", len(spans))
+	}
+	s := spans[0]
+	if s.Name() != "GET /jobs/{id}" || s.SpanKind() != trace.SpanKindServer {
+		t.Errorf("span = %q (%v)", s.Name(), s.SpanKind())
+	}
+	if s.SpanContext().TraceID().String() != "4bf92f3577b34da6a3ce929d0e0e4736" {
+		t.Errorf("trace id = %s, want the caller's", s.SpanContext().TraceID())
+	}
+	if inner.SpanID() != s.SpanContext().SpanID() {
+		t.Error("handler does not run inside the server span")
+	}
+	if s.Status().Description != http.StatusText(http.StatusBadGateway) {
+		t.Errorf("status = %+v", s.Status())
+	}
+}
+
+func TestRouteName(t *testing.T) {
+	cases := map[string]string{
+		"/scrape-twitter": "/scrape-twitter",
+		"/jobs/abc":       "/jobs/{id}",
+		"/jobs":           "/jobs",
+		"/wp-admin/x.php": "unmatched",
+		"/scrape/":        "unmatched",
+	}
+	for path, want := range cases {
+		if got := routeName(path); got != want {
+			t.Errorf("routeName(%q) = %q, want %q", path, got, want)
+		}
+	}
+}
@@ -0,0 +1,194 @@
+package internal
+
+import (
+	"context"
+	"crypto/subtle"
+	"fmt"
+	"os"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// APIKey : 클라이언트 하나. 할당량은 auth.window 마다 초기화되고 0이면 제한하지 않는다.
+type APIKey struct {
+	Name           string `yaml:"name"`
+	Key            string `yaml:"key"`
+	Admin          bool   `yaml:"admin"`           // /usage 에서 모든 키의 사용량을 볼 수 있음
+	Requests       int    `yaml:"requests"`        // 기간당 스크래핑 수 (배치는 항목 수만큼)
+	BrowserSeconds int    `yaml:"browser_seconds"` // 기간당 브라우저 탭/세션 사용 시간(초)
+}
+
+// KeyUsage : 키별 사용량 보고
+type KeyUsage struct {
+	Name                string    `json:"name"`
+	Requests            int       `json:"requests"`
+	BrowserSeconds      float64   `json:"browser_seconds"`
+	RequestQuota        int       `json:"request_quota,omitempty"`
+	BrowserSecondsQuota int       `json:"browser_seconds_quota,omitempty"`
+	WindowStart         time.Time `json:"window_start"`
+	ResetsAt            time.Time `json:"resets_at"`
+}
+
+// KeyStore : API 키 목록과 키별 사용량. 키가 하나도 없으면 인증을 하지 않는다.
+type KeyStore struct {
+	window time.Duration
+
+	mu    sync.Mutex
+	keys  []APIKey
+	usage map[string]*KeyUsage // 키 이름별
+}
+
+// LoadKeyStore : auth.keys 와 auth.keys_file 의 키를 합친다.
+func LoadKeyStore(cfg AuthConfig) (*KeyStore, error) {
+	keys := append([]APIKey(nil), cfg.Keys...)
+	if cfg.KeysFile != "" {
+		raw, err := os.ReadFile(cfg.KeysFile)
+		if err != nil {
+			return nil, fmt.Errorf("read keys file: %w", err)
+		}
+		var fileKeys []APIKey
+		if err := yaml.Unmarshal(raw, &fileKeys); err != nil {
+			return nil, fmt.Errorf("parse keys file %s: %w", cfg.KeysFile, err)
+		}
+		keys = append(keys, fileKeys...)
+	}
+
+	seen := map[string]bool{}
+	for _, k := range keys {
+		if k.Name == "" || k.Key == "" {
+			return nil, fmt.Errorf("api key needs name and key: %q", k.Name)
+		}
+		if seen[k.Name] {
+			return nil, fmt.Errorf("duplicate api key name: %q", k.Name)
+		}
+		seen[k.Name] = true
+	}
+	return &KeyStore{window: cfg.Window, keys: keys, usage: map[string]*KeyUsage{}}, nil
+}
+
+// Enabled : 키가 하나라도 있으면 인증을 켠다.
+func (s *KeyStore) Enabled() bool { return len(s.keys) > 0 }
+
+// Lookup : 요청에 실린 키 값으로 키를 찾는다.
+func (s *KeyStore) Lookup(secret string) (APIKey, bool) {
+	if secret == "" {
+		return APIKey{}, false
+	}
+	for _, k := range s.keys {
+		if subtle.ConstantTimeCompare([]byte(k.Key), []byte(secret)) == 1 {
+			return k, true
+		}
+	}
+	return APIKey{}, false
+}
+
+// Get : 이름으로 키를 찾는다 (비동기 작업의 사용량 기록용).
+func (s *KeyStore) Get(name string) (APIKey, bool) {
+	for _, k := range s.keys {
+		if k.Name == name {
+			return k, true
+		}
+	}
+	return APIKey{}, false
+}
+
+// Charge : 할당량을 확인하고 스크래핑 n건을 센다. 남은 할당량보다 많으면 하나도 세지 않고 거절한다.
+func (s *KeyStore) Charge(key APIKey, n int) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	u := s.usageLocked(key)
+	if key.Requests > 0 && u.Requests+n > key.Requests {
+		return NewError(CodeQuotaExceeded, "request quota exceeded for %s (resets at %s)", key.Name, u.ResetsAt.Format(time.RFC3339))
+	}
+	if key.BrowserSeconds > 0 && u.BrowserSeconds >= float64(key.BrowserSeconds) {
+		return NewError(CodeQuotaExceeded, "browser time quota exceeded for %s (resets at %s)", key.Name, u.ResetsAt.Format(time.RFC3339))
+	}
+	u.Requests += n
+	return nil
+}
+
+// Refund : Charge로 센 n건을 되돌린다 (작업 큐가 받아 주지 않았을 때).
+func (s *KeyStore) Refund(key APIKey, n int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	u := s.usageLocked(key)
+	u.Requests = max(u.Requests-n, 0)
+}
+
+// AddBrowserTime : 요청이 끝난 뒤 브라우저를 쓴 시간을 더한다.
+func (s *KeyStore) AddBrowserTime(key APIKey, d time.Duration) {
+	if d <= 0 {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.usageLocked(key).BrowserSeconds += d.Seconds()
+}
+
+// Usage : 키 하나의 현재 기간 사용량
+func (s *KeyStore) Usage(key APIKey) KeyUsage {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return *s.usageLocked(key)
+}
+
+// UsageAll : 모든 키의 사용량 (이름순)
+func (s *KeyStore) UsageAll() []KeyUsage {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	all := make([]KeyUsage, 0, len(s.keys))
+	for _, k := range s.keys {
+		all = append(all, *s.usageLocked(k))
+	}
+	sort.Slice(all, func(i, j int) bool { return all[i].Name < all[j].Name })
+	return all
+}
+
+// usageLocked : 기간이 지났으면 사용량을 새로 시작한다. s.mu를 잡고 호출한다.
+func (s *KeyStore) usageLocked(key APIKey) *KeyUsage {
+	now := time.Now()
+	u, ok := s.usage[key.Name]
+	if !ok || !now.Before(u.ResetsAt) {
+		u = &KeyUsage{Name: key.Name, WindowStart: now, ResetsAt: now.Add(s.window)}
+		s.usage[key.Name] = u
+	}
+	u.RequestQuota, u.BrowserSecondsQuota = key.Requests, key.BrowserSeconds
+	return u
+}
+
+// BrowserMeter : 요청 하나가 브라우저 탭/세션을 빌려 쓴 시간
+type BrowserMeter struct {
+	nanos atomic.Int64
+}
+
+func (m *BrowserMeter) Elapsed() time.Duration { return time.Duration(m.nanos.Load()) }
+
+type browserMeterKey struct{}
+
+// WithBrowserMeter : 이 컨텍스트로 실행한 브라우저 엔진의 사용 시간을 m에 더하게 한다.
+func WithBrowserMeter(ctx context.Context, m *BrowserMeter) context.Context {
+	return context.WithValue(ctx, browserMeterKey{}, m)
+}
+
+// addBrowserTime : ctx의 BrowserMeter에 d를 더한다. 미터가 없으면 아무것도 안 한다.
+func addBrowserTime(ctx context.Context, d time.Duration) {
+	if m, _ := ctx.Value(browserMeterKey{}).(*BrowserMeter); m != nil {
+		m.nanos.Add(int64(d))
+	}
+}
+
+// meterBrowser : 탭/세션을 빌린 직후 호출하고, 돌려받은 함수를 돌려줄 때 호출한다.
+//
+//	defer meterBrowser(ctx)()
+func meterBrowser(ctx context.Context) func() {
+	m, _ := ctx.Value(browserMeterKey{}).(*BrowserMeter)
+	if m == nil {
+		return func() {}
+	}
+	start := time.Now()
+	return func() { m.nanos.Add(int64(time.Since(start))) }
+}
@@ -0,0 +1,74 @@
+package internal
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestLoadKeyStore(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "keys.yaml")
+	os.WriteFile(path, []byte("- name: bot\n  key: bot-secret\n  browser_seconds: 1\n"), 0644)
+
+	store, err := LoadKeyStore(AuthConfig{
+		Keys:     []APIKey{{Name: "web", Key: "web-secret"}},
+		KeysFile: path,
+		Window:   time.Hour,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := store.Lookup("bot-secret"); !ok {
+		t.Error("key from file not loaded")
+	}
+	if _, ok := store.Lookup(""); ok {
+		t.Error("empty key accepted")
+	}
+
+	if _, err := LoadKeyStore(AuthConfig{Keys: []APIKey{{Name: "a", Key: "x"}, {Name: "a", Key: "y"}}}); err == nil {
+		t.Error("duplicate names accepted")
+	}
+}
+
+func TestKeyStoreBrowserQuota(t *testing.T) {
+	store, _ := LoadKeyStore(AuthConfig{Keys: []APIKey{{Name: "bot", Key: "k", BrowserSeconds: 1}}, Window: time.Hour})
+	key, _ := store.Get("bot")
+
+	if err := store.Charge(key, 1); err != nil {
+		t.Fatal(err)
+	}
+	meter := &BrowserMeter{}
+	stop := meterBrowser(WithBrowserMeter(context.Background(), meter))
+	time.Sleep(5 * time.Millisecond)
+	stop()
+	store.AddBrowserTime(key, meter.Elapsed()+time.Second)
+
+	err := store.Charge(key, 1)
+	if se := Classify(err); err == nil || se.Code != CodeQuotaExceeded {
+		t.Fatalf("expected quota_exceeded, got %v", err)
+	}
+	if u := store.Usage(key); u.Requests != 1 || u.BrowserSeconds < 1 {
+		t.Errorf("usage = %+v", u)
+	}
+}
+
+func TestChargeCountsScrapes(t *testing.T) {
+	store, _ := LoadKeyStore(AuthConfig{Keys: []APIKey{{Name: "bot", Key: "k", Requests: 3}}, Window: time.Hour})
+	key, _ := store.Get("bot")
+
+	if err := store.Charge(key, 2); err != nil {
+		t.Fatal(err)
+	}
+	// 남은 할당량(1)보다 큰 배치는 통째로 거절하고 세지 않는다
+	if err := store.Charge(key, 2); Classify(err).Code != CodeQuotaExceeded {
+		t.Fatalf("expected quota_exceeded, got %v", err)
+	}
+	if err := store.Charge(key, 1); err != nil {
+		t.Fatal(err)
+	}
+	if u := store.Usage(key); u.Requests != 3 {
+		t.Errorf("usage = %+v", u)
+	}
+}
@@ -0,0 +1,248 @@
+package internal
+
+import (
+	"container/list"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+// CacheInfo : 결과를 가져온 시각과 캐시 적중 여부. TweetData/MetaData/Envelope에 들어간다.
+// 인용한 트윗처럼 결과 안에 들어간 TweetData는 시각이 비어 있어서 fetched_at을 빼고 내보낸다.
+type CacheInfo struct {
+	FetchedAt time.Time `json:"fetched_at,omitzero"`
+	CacheHit  bool      `json:"-"` // X-Cache 헤더용
+}
+
+func (i *CacheInfo) cacheInfo() *CacheInfo { return i }
+
+// cacheEntry : 메모리와 디스크에 같은 모양으로 저장한다. Data는 결과 JSON
+type cacheEntry struct {
+	Key       string          `json:"key"`
+	Data      json.RawMessage `json:"data"`
+	FetchedAt time.Time       `json:"fetched_at"`
+	ExpiresAt time.Time       `json:"expires_at"`
+}
+
+// Cache : 스크래핑 결과 캐시. 메모리 LRU(cache.size 개) 앞에 두고,
+// cache.dir을 지정하면 디스크에도 저장해서 재시작 후에도 쓴다.
+type Cache struct {
+	cfg CacheConfig
+
+	mu    sync.Mutex
+	lru   *list.List // 앞쪽이 최근에 쓴 항목
+	items map[string]*list.Element
+}
+
+func NewCache(cfg CacheConfig) (*Cache, error) {
+	if cfg.Dir != "" {
+		if err := os.MkdirAll(cfg.Dir, 0o755); err != nil {
+			return nil, err
+		}
+	}
+	return &Cache{cfg: cfg, lru: list.New(), items: map[string]*list.Element{}}, nil
+}
+
+// TTL : 결과 종류별 보관 기간. 0이면 그 종류는 캐시하지 않는다.
+func (c *Cache) TTL(typ string) time.Duration {
+	if typ == TypeTweet {
+		return c.cfg.TweetTTL
+	}
+	return c.cfg.MetaTTL
+}
+
+// Get : key의 결과를 v에 채우고 가져온 시각을 돌려준다. 메모리에 없으면 디스크를 본다.
+func (c *Cache) Get(key string, v any) (time.Time, bool) {
+	entry, ok := c.getMemory(key)
+	if !ok {
+		if entry, ok = c.getDisk(key); ok {
+			c.putMemory(entry)
+		}
+	}
+	if !ok {
+		return time.Time{}, false
+	}
+	if err := json.Unmarshal(entry.Data, v); err != nil {
+		return time.Time{}, false
+	}
+	return entry.FetchedAt, true
+}
+
+// Put : 결과를 ttl 동안 저장한다.
+func (c *Cache) Put(key string, v any, fetchedAt time.Time, ttl time.Duration) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return
+	}
+	entry := &cacheEntry{Key: key, Data: data, FetchedAt: fetchedAt, ExpiresAt: fetchedAt.Add(ttl)}
+	c.putMemory(entry)
+	c.putDisk(entry)
+}
+
+func (c *Cache) getMemory(key string) (*cacheEntry, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	el, ok := c.items[key]
+	if !ok {
+		return nil, false
+	}
+	entry := el.Value.(*cacheEntry)
+	if time.Now().After(entry.ExpiresAt) {
+		c.lru.Remove(el)
+		delete(c.items, key)
+		return nil, false
+	}
+	c.lru.MoveToFront(el)
+	return entry, true
+}
+
+func (c *Cache) putMemory(entry *cacheEntry) {
+	if c.cfg.Size <= 0 {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if el, ok := c.items[entry.Key]; ok {
+		el.Value = entry
+		c.lru.MoveToFront(el)
+		return
+	}
+	c.items[entry.Key] = c.lru.PushFront(entry)
+	for c.lru.Len() > c.cfg.Size {
+		oldest := c.lru.Back()
+		c.lru.Remove(oldest)
+		delete(c.items, oldest.Value.(*cacheEntry).Key)
+	}
+}
+
+// diskPath : 키를 해시해서 파일 이름으로 쓴다.
+func (c *Cache) diskPath(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return filepath.Join(c.cfg.Dir, hex.EncodeToString(sum[:])+".json")
+}
+
+func (c *Cache) getDisk(key string) (*cacheEntry, bool) {
+	if c.cfg.Dir == "" {
+		return nil, false
+	}
+	path := c.diskPath(key)
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, false
+	}
+	var entry cacheEntry
+	if err := json.Unmarshal(b, &entry); err != nil || entry.Key != key || time.Now().After(entry.ExpiresAt) {
+		_ = os.Remove(path)
+		return nil, false
+	}
+	return &entry, true
+}
+
+// putDisk : 임시 파일에 쓰고 이름을 바꿔서 읽는 쪽이 반쯤 쓴 파일을 보지 않게 한다.
+func (c *Cache) putDisk(entry *cacheEntry) {
+	if c.cfg.Dir == "" {
+		return
+	}
+	b, err := json.Marshal(entry)
+	if err != nil {
+		return
+	}
+	tmp, err := os.CreateTemp(c.cfg.Dir, "tmp-*")
+	if err != nil {
+		slog.Warn("⚠️ Failed to write cache", "err", err)
+		return
+	}
+	_, err = tmp.Write(b)
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = os.Rename(tmp.Name(), c.diskPath(entry.Key))
+	}
+	if err != nil {
+		_ = os.Remove(tmp.Name())
+		slog.Warn("⚠️ Failed to write cache", "err", err)
+	}
+}
+
+// cacheSweepInterval : 디스크 캐시에서 만료된 파일을 지우는 주기
+var cacheSweepInterval = 10 * time.Minute
+
+// Run : ctx가 끝날 때까지 주기적으로 디스크 캐시를 정리한다. 파일은 읽힐 때만 지워지므로
+// 다시 요청되지 않은 결과가 cache.dir에 계속 쌓이지 않게 한다.
+func (c *Cache) Run(ctx context.Context) {
+	if c.cfg.Dir == "" {
+		return
+	}
+	ticker := time.NewTicker(cacheSweepInterval)
+	defer ticker.Stop()
+	for {
+		if removed := c.sweep(time.Now()); removed > 0 {
+			slog.Info("🧹 Cache files expired", "removed", removed)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// sweep : 만료됐거나 읽을 수 없는 캐시 파일과 남겨진 임시 파일을 지우고 지운 수를 돌려준다.
+func (c *Cache) sweep(now time.Time) int {
+	files, err := os.ReadDir(c.cfg.Dir)
+	if err != nil {
+		slog.Warn("⚠️ Failed to sweep cache", "err", err)
+		return 0
+	}
+	removed := 0
+	for _, f := range files {
+		path := filepath.Join(c.cfg.Dir, f.Name())
+		switch {
+		case f.IsDir():
+			continue
+		case strings.HasPrefix(f.Name(), "tmp-"):
+			// 쓰다가 멈춘 임시 파일. 지금 쓰는 중일 수 있으니 오래된 것만
+			if info, err := f.Info(); err != nil || now.Sub(info.ModTime()) < time.Hour {
+				continue
+			}
+		case strings.HasSuffix(f.Name(), ".json"):
+			var entry struct {
+				ExpiresAt time.Time `json:"expires_at"`
+			}
+			if b, err := os.ReadFile(path); err == nil && json.Unmarshal(b, &entry) == nil && now.Before(entry.ExpiresAt) {
+				continue
+			}
+		default:
+			continue
+		}
+		if os.Remove(path) == nil {
+			removed++
+		}
+	}
+	return removed
+}
+
+type noCacheKey struct{}
+
+// WithNoCache : 캐시를 읽지 않고 새로 스크래핑하게 한다 (Cache-Control: no-cache). 결과는 다시 저장한다.
+func WithNoCache(ctx context.Context) context.Context {
+	return context.WithValue(ctx, noCacheKey{}, true)
+}
+
+func noCache(ctx context.Context) bool {
+	v, _ := ctx.Value(noCacheKey{}).(bool)
+	return v
+}
+
+// cacheKey : 결과 종류 + 정규화한 URL
+func cacheKey(typ, pageURL string) string {
+	return typ + " " + CanonicalURL(pageURL)
+}
@@ -0,0 +1,140 @@
+package internal
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// countingScraper : 호출 횟수를 세는 메타 스크래퍼
+type countingScraper struct {
+	calls atomic.Int32
+}
+
+func (s *countingScraper) Name() string { return "counting" }
+func (s *countingScraper) ScrapeTweet(ctx context.Context, url string, opts TweetOptions) (*TweetData, error) {
+	return nil, ErrUnsupported
+}
+func (s *countingScraper) ScrapeMeta(ctx context.Context, url string, opts MetaOptions) (*MetaData, error) {
+	s.calls.Add(1)
+	return &MetaData{Title: "title", URL: url}, nil
+}
+
+func TestChainCache(t *testing.T) {
+	scraper := &countingScraper{}
+	registry := NewRegistry()
+	registry.Register(scraper)
+	chain, err := NewChain(registry, ChainPolicy{Default: []string{"counting"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	cache, err := NewCache(CacheConfig{Size: 10, MetaTTL: time.Hour})
+	if err != nil {
+		t.Fatal(err)
+	}
+	chain.UseCache(cache)
+
+	ctx := context.Background()
+	first, err := chain.ScrapeMeta(ctx, "", "https://kre.pe/abc?utm_source=x", MetaOptions{})
+	if err != nil || first.CacheHit || first.FetchedAt.IsZero() {
+		t.Fatalf("first = %+v, %v", first, err)
+	}
+	second, err := chain.ScrapeMeta(ctx, "", "https://KRE.PE/abc/", MetaOptions{})
+	if err != nil || !second.CacheHit || !second.FetchedAt.Equal(first.FetchedAt) || second.Engine != "counting" {
+		t.Fatalf("second = %+v, %v", second, err)
+	}
+	third, err := chain.ScrapeMeta(WithNoCache(ctx), "", "https://kre.pe/abc", MetaOptions{})
+	if err != nil || third.CacheHit {
+		t.Fatalf("third = %+v, %v", third, err)
+	}
+	if n := scraper.calls.Load(); n != 2 {
+		t.Errorf("scraper called %d times, want 2", n)
+	}
+}
+
+func TestCacheEvictionAndExpiry(t *testing.T) {
+	cache, _ := NewCache(CacheConfig{Size: 2})
+	now := time.Now()
+	cache.Put("a", "A", now, time.Hour)
+	cache.Put("b", "B", now, time.Hour)
+	var v string
+	cache.Get("a", &v) // a를 최근 항목으로
+	cache.Put("c", "C", now, time.Hour)
+
+	if _, ok := cache.Get("b", &v); ok {
+		t.Error("least recently used entry was not evicted")
+	}
+	if _, ok := cache.Get("a", &v); !ok || v != "A" {
+		t.Errorf("a = %q, %v", v, ok)
+	}
+
+	cache.Put("old", "X", now.Add(-2*time.Hour), time.Hour)
+	if _, ok := cache.Get("old", &v); ok {
+		t.Error("expired entry returned")
+	}
+}
+
+func TestCacheDiskSurvivesRestart(t *testing.T) {
+	dir := t.TempDir()
+	cache, err := NewCache(CacheConfig{Size: 10, Dir: dir})
+	if err != nil {
+		t.Fatal(err)
+	}
+	fetchedAt := time.Now().UTC().Truncate(time.Second)
+	cache.Put("tweet https://x.com/a/status/1", &TweetData{Text: "hi"}, fetchedAt, time.Hour)
+
+	restarted, _ := NewCache(CacheConfig{Size: 10, Dir: dir})
+	var got TweetData
+	at, ok := restarted.Get("tweet https://x.com/a/status/1", &got)
+	if !ok || got.Text != "hi" || !at.Equal(fetchedAt) {
+		t.Errorf("got %+v at %v, ok=%v", got, at, ok)
+	}
+
+	memoryOnly, _ := NewCache(CacheConfig{Size: 10})
+	if _, ok := memoryOnly.Get("tweet https://x.com/a/status/1", &got); ok {
+		t.Error("memory-only cache read from disk")
+	}
+}
+
+func TestCacheSweep(t *testing.T) {
+	dir := t.TempDir()
+	cache, _ := NewCache(CacheConfig{Dir: dir})
+	now := time.Now()
+	cache.Put("meta https://old.example", &MetaData{Title: "old"}, now.Add(-2*time.Hour), time.Hour)
+	cache.Put("meta https://new.example", &MetaData{Title: "new"}, now, time.Hour)
+	os.WriteFile(filepath.Join(dir, "broken.json"), []byte("{"), 0o644)
+	stale := filepath.Join(dir, "tmp-123")
+	os.WriteFile(stale, nil, 0o644)
+	os.Chtimes(stale, now.Add(-2*time.Hour), now.Add(-2*time.Hour))
+	os.WriteFile(filepath.Join(dir, "tmp-456"), nil, 0o644) // 지금 쓰는 중
+
+	if removed := cache.sweep(now); removed != 3 {
+		t.Errorf("removed = %d, want 3", removed)
+	}
+	files, _ := os.ReadDir(dir)
+	if len(files) != 2 {
+		t.Errorf("left %v", files)
+	}
+	var got MetaData
+	if _, ok := cache.Get("meta https://new.example", &got); !ok || got.Title != "new" {
+		t.Errorf("fresh entry swept: %+v", got)
+	}
+}
+
+func TestCanonicalURL(t *testing.T) {
+	cases := map[string]string{
+		"https://twitter.com/Naeng2_/status/123?s=20&t=abc": "https://x.com/naeng2_/status/123",
+		"https://mobile.x.com/naeng2_/status/123/":          "https://x.com/naeng2_/status/123",
+		"HTTPS://Kre.PE:443/V5LG#top":                       "https://kre.pe/V5LG",
+		"https://example.com/?b=2&utm_source=x&a=1":         "https://example.com/?a=1&b=2",
+		"http://example.com:8080/page/":                     "http://example.com:8080/page",
+	}
+	for in, want := range cases {
+		if got := CanonicalURL(in); got != want {
+			t.Errorf("CanonicalURL(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
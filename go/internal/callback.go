@@ -0,0 +1,118 @@
+package internal
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net"
+	"net/http"
+	"net/netip"
+	"strings"
+	"syscall"
+	"time"
+)
+
+// callback : 끝난 작업을 callback_url로 POST. 실패하면 1초, 2초 쉬고 다시 보낸다.
+func (q *JobQueue) callback(job Job) {
+	body, _ := json.Marshal(job)
+	var err error
+	for attempt := 0; attempt < 3; attempt++ {
+		if attempt > 0 {
+			select {
+			case <-time.After(time.Duration(attempt) * time.Second):
+			case <-q.ctx.Done():
+				return
+			}
+		}
+		if err = q.post(job.CallbackURL, body); err == nil {
+			return
+		}
+	}
+	slog.Warn("⚠️ Job callback failed", "job_id", job.ID, "err", err)
+}
+
+func (q *JobQueue) post(target string, body []byte) error {
+	req, err := http.NewRequestWithContext(q.ctx, http.MethodPost, target, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := q.client.Do(req)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("callback status %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// newCallbackClient : 공개 주소에만 연결하는 콜백용 클라이언트.
+// 주소는 연결하는 순간(Dialer.Control)에 검사하므로 DNS가 중간에 바뀌거나 리다이렉트돼도 내부망에 닿지 않는다.
+// 프록시를 거치면 프록시 주소만 검사하게 되므로 프록시는 쓰지 않는다.
+func newCallbackClient() *http.Client {
+	dialer := &net.Dialer{Timeout: 5 * time.Second, Control: dialPublicOnly}
+	return &http.Client{
+		Timeout: 10 * time.Second,
+		Transport: &http.Transport{
+			Proxy:               nil,
+			DialContext:         dialer.DialContext,
+			TLSHandshakeTimeout: 5 * time.Second,
+			MaxIdleConns:        10,
+			IdleConnTimeout:     90 * time.Second,
+		},
+	}
+}
+
+// dialPublicOnly : 루프백, 사설망, 링크 로컬(169.254.169.254 같은 메타데이터 서버) 등 공개되지 않은 주소로의 연결을 막는다.
+func dialPublicOnly(network, address string, _ syscall.RawConn) error {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return err
+	}
+	addr, err := netip.ParseAddr(host)
+	if err != nil {
+		return err
+	}
+	if !PublicAddr(addr) {
+		return NewError(CodeInvalidURL, "callback address %s is not public", addr)
+	}
+	return nil
+}
+
+// nonPublicPrefixes : netip 판별 함수로 잡히지 않는 공개되지 않은 대역
+var nonPublicPrefixes = []netip.Prefix{
+	netip.MustParsePrefix("0.0.0.0/8"),     // "이 네트워크"
+	netip.MustParsePrefix("100.64.0.0/10"), // 통신사 NAT
+	netip.MustParsePrefix("198.18.0.0/15"), // 벤치마크용
+	netip.MustParsePrefix("64:ff9b::/96"),  // NAT64 (안쪽 IPv4를 우회할 수 있다)
+}
+
+// PublicAddr : 인터넷에서 닿을 수 있는 주소인지
+func PublicAddr(addr netip.Addr) bool {
+	addr = addr.Unmap()
+	if !addr.IsValid() || addr.IsLoopback() || addr.IsPrivate() || addr.IsUnspecified() ||
+		addr.IsLinkLocalUnicast() || addr.IsLinkLocalMulticast() || addr.IsInterfaceLocalMulticast() || addr.IsMulticast() {
+		return false
+	}
+	for _, p := range nonPublicPrefixes {
+		if p.Contains(addr) {
+			return false
+		}
+	}
+	return true
+}
+
+// CheckCallbackHost : 요청을 받을 때 바로 알 수 있는 잘못된 콜백 주소(이름이 localhost이거나 공개되지 않은 IP)를 거른다.
+// 도메인 이름이 가리키는 주소는 보낼 때 newCallbackClient가 다시 검사한다.
+func CheckCallbackHost(host string) error {
+	if host = strings.ToLower(strings.TrimSuffix(host, ".")); host == "localhost" || strings.HasSuffix(host, ".localhost") {
+		return NewError(CodeInvalidURL, "callback host %q is not public", host)
+	}
+	if addr, err := netip.ParseAddr(host); err == nil && !PublicAddr(addr) {
+		return NewError(CodeInvalidURL, "callback address %s is not public", addr)
+	}
+	return nil
+}
@@ -0,0 +1,237 @@
+package internal
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/url"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// ChainRule : 특정 도메인에서 시도할 엔진 순서. 예) x.com → [chromedp, selenium]
+type ChainRule struct {
+	Pattern string   `yaml:"pattern"`
+	Engines []string `yaml:"engines"`
+}
+
+// ChainPolicy : 기본 엔진 순서와 도메인별 예외
+type ChainPolicy struct {
+	Default []string
+	Rules   []ChainRule
+}
+
+// Chain : 엔진을 순서대로 시도하고, 결과가 불완전하면 다음 엔진으로 넘어간다.
+// 예) http → chromedp → selenium
+type Chain struct {
+	registry *Registry
+	policy   ChainPolicy
+	cache    *Cache
+	limiter  *DomainLimiter
+	flights  flightGroup
+}
+
+func NewChain(registry *Registry, policy ChainPolicy) (*Chain, error) {
+	check := func(names []string) error {
+		for _, name := range names {
+			if _, ok := registry.Get(name); !ok {
+				return fmt.Errorf("unknown engine in chain: %q", name)
+			}
+		}
+		return nil
+	}
+	if len(policy.Default) == 0 {
+		return nil, errors.New("empty default chain")
+	}
+	if err := check(policy.Default); err != nil {
+		return nil, err
+	}
+	for _, rule := range policy.Rules {
+		if err := check(rule.Engines); err != nil {
+			return nil, err
+		}
+	}
+	return &Chain{registry: registry, policy: policy}, nil
+}
+
+// UseCache : 결과 캐시를 붙인다. nil이면 캐시 없이 매번 스크래핑한다.
+func (c *Chain) UseCache(cache *Cache) { c.cache = cache }
+
+// UseMaxDuration : 같은 요청을 합쳐서 돌리는 스크래핑 하나의 최대 시간 (timeouts.max)
+func (c *Chain) UseMaxDuration(d time.Duration) { c.flights.max = d }
+
+// UseLimiter : 대상 도메인별 요청 제한을 붙인다. 캐시에서 찾은 결과는 제한하지 않는다.
+func (c *Chain) UseLimiter(limiter *DomainLimiter) { c.limiter = limiter }
+
+// Engines : pageURL에 적용할 엔진 순서. 먼저 등록된 도메인 규칙이 우선한다.
+func (c *Chain) Engines(pageURL string) []string {
+	if u, err := url.Parse(pageURL); err == nil && u.Hostname() != "" {
+		for _, rule := range c.policy.Rules {
+			if MatchHost(u.Hostname(), rule.Pattern) {
+				return rule.Engines
+			}
+		}
+	}
+	return c.policy.Default
+}
+
+// plan : engine을 지정했으면 그 엔진만, 아니면 정책에 따른 순서
+func (c *Chain) plan(engine, pageURL string) ([]Scraper, error) {
+	names := c.Engines(pageURL)
+	if engine != "" {
+		names = []string{engine}
+	}
+	scrapers := make([]Scraper, 0, len(names))
+	for _, name := range names {
+		s, ok := c.registry.Get(name)
+		if !ok {
+			return nil, NewError(CodeBadRequest, "unknown engine: %q", name)
+		}
+		scrapers = append(scrapers, s)
+	}
+	return scrapers, nil
+}
+
+// ScrapeMeta : 제목이 채워진 결과가 나올 때까지 엔진을 차례로 시도한다.
+func (c *Chain) ScrapeMeta(ctx context.Context, engine, pageURL string, opts MetaOptions) (*MetaData, error) {
+	scrapers, err := c.plan(engine, pageURL)
+	if err != nil {
+		return nil, err
+	}
+	return fetch(ctx, c, TypeMeta, cacheKey(TypeMeta, pageURL), engine, pageURL, func(ctx context.Context) (*MetaData, error) {
+		return runChain(ctx, TypeMeta, pageURL, c.hostLabel(ctx, pageURL), scrapers, func(ctx context.Context, s Scraper) (*MetaData, error) {
+			return s.ScrapeMeta(ctx, pageURL, opts)
+		}, func(m *MetaData, name string) bool {
+			m.Engine = name
+			return m.complete()
+		})
+	})
+}
+
+// ScrapeTweet : 본문이나 이미지가 있는 결과가 나올 때까지 엔진을 차례로 시도한다.
+// 타래(opts.Thread)는 깊이별로 따로 캐시한다.
+func (c *Chain) ScrapeTweet(ctx context.Context, engine, tweetURL string, opts TweetOptions) (*TweetData, error) {
+	scrapers, err := c.plan(engine, tweetURL)
+	if err != nil {
+		return nil, err
+	}
+	key := cacheKey(TypeTweet, tweetURL)
+	if opts.Thread > 0 {
+		key += fmt.Sprintf(" thread=%d", opts.Thread)
+	}
+	return fetch(ctx, c, TypeTweet, key, engine, tweetURL, func(ctx context.Context) (*TweetData, error) {
+		return runChain(ctx, TypeTweet, tweetURL, c.hostLabel(ctx, tweetURL), scrapers, func(ctx context.Context, s Scraper) (*TweetData, error) {
+			return s.ScrapeTweet(ctx, tweetURL, opts)
+		}, func(t *TweetData, name string) bool {
+			t.Engine = name
+			return t.complete()
+		})
+	})
+}
+
+// fetch : 캐시에 있으면 그 결과를 돌려준다. 없으면 같은 요청(캐시 키, 엔진)이
+// 이미 진행 중인지 보고 그 결과를 함께 받거나, 새로 scrape를 실행해 캐시할 만한 결과만 저장한다.
+func fetch[T any, P interface {
+	*T
+	cacheInfo() *CacheInfo
+	cacheable() bool
+}](ctx context.Context, c *Chain, typ, key, engine, pageURL string, scrape func(context.Context) (P, error)) (P, error) {
+	cache := c.cache
+	if cache != nil && cache.TTL(typ) <= 0 {
+		cache = nil
+	}
+	if cache != nil && !noCache(ctx) {
+		var v T
+		if fetchedAt, ok := cache.Get(key, &v); ok {
+			data := P(&v)
+			*data.cacheInfo() = CacheInfo{FetchedAt: fetchedAt, CacheHit: true}
+			return data, nil
+		}
+	}
+
+	v, shared, err := c.flights.Do(ctx, key+" "+engine, func(ctx context.Context) (any, error) {
+		if c.limiter != nil {
+			release, err := c.limiter.Acquire(ctx, pageURL)
+			if err != nil {
+				return nil, err
+			}
+			defer release()
+		}
+		data, err := scrape(ctx)
+		if err != nil {
+			return nil, err
+		}
+		data.cacheInfo().FetchedAt = time.Now().UTC()
+		if cache != nil && data.cacheable() {
+			cache.Put(key, data, data.cacheInfo().FetchedAt, cache.TTL(typ))
+		}
+		return data, nil
+	})
+	if shared {
+		coalescedCalls.Add(typ, 1)
+		coalescedTotal.WithLabelValues(typ).Inc()
+	}
+	if err != nil {
+		return nil, err
+	}
+	return v.(P), nil
+}
+
+// runChain : 완전한 결과를 처음 낸 엔진의 결과를 돌려준다. host는 지표용 호스트 라벨 (Chain.hostLabel)
+// 모두 불완전하면 마지막 불완전 결과를, 결과가 하나도 없으면 마지막 에러를 돌려준다.
+// 없는 페이지처럼 엔진을 바꿔도 같은 에러는 바로 돌려준다.
+func runChain[T any, P interface {
+	*T
+	emptyFields() []string
+}](ctx context.Context, typ, pageURL, host string, scrapers []Scraper, scrape func(context.Context, Scraper) (P, error), complete func(P, string) bool) (P, error) {
+	var (
+		partial P
+		lastErr error
+	)
+	extractor := extractorLabel(ctx, typ)
+	for _, s := range scrapers {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		start := time.Now()
+		spanCtx, span := StartSpan(ctx, "engine.scrape",
+			attribute.String("scraper.engine", s.Name()), attribute.String("scraper.extractor", extractor), attribute.String("url.full", pageURL))
+		data, err := scrape(spanCtx, s)
+		if errors.Is(err, ErrUnsupported) {
+			span.End()
+			continue
+		}
+		EndSpan(span, err)
+		scrapeDuration.WithLabelValues(s.Name(), extractor, host).Observe(time.Since(start).Seconds())
+		if err != nil {
+			scrapeOutcomes.WithLabelValues(s.Name(), extractor, string(Classify(err).Code)).Inc()
+		}
+		if isTerminal(err) {
+			return nil, err
+		}
+		if err != nil {
+			slog.WarnContext(ctx, "↪️ Engine failed, trying next engine", "engine", s.Name(), "err", err)
+			lastErr = err
+			continue
+		}
+		for _, field := range data.emptyFields() {
+			emptyFields.WithLabelValues(s.Name(), extractor, field).Inc()
+		}
+		if complete(data, s.Name()) {
+			scrapeOutcomes.WithLabelValues(s.Name(), extractor, "ok").Inc()
+			return data, nil
+		}
+		scrapeOutcomes.WithLabelValues(s.Name(), extractor, "incomplete").Inc()
+		slog.InfoContext(ctx, "↪️ Engine returned incomplete result, trying next engine", "engine", s.Name())
+		partial = data
+	}
+	if partial != nil {
+		return partial, nil
+	}
+	if lastErr == nil {
+		lastErr = NewError(CodeUnsupported, "no engine could handle the request")
+	}
+	return nil, lastErr
+}
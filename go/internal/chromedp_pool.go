@@ -0,0 +1,333 @@
+package internal
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"sync"
+
+	"github.com/chromedp/cdproto/browser"
+	"github.com/chromedp/cdproto/cdp"
+	"github.com/chromedp/cdproto/emulation"
+	"github.com/chromedp/chromedp"
+)
+
+// ErrPoolClosed : 닫힌 풀에서 탭/세션을 빌리려 할 때
+var ErrPoolClosed = errors.New("pool closed")
+
+// Tab : 풀에서 빌려준 탭. Context()로 chromedp.Run을 실행한다.
+type Tab struct {
+	ctx    context.Context
+	cancel context.CancelFunc
+}
+
+func (t *Tab) Context() context.Context { return t.ctx }
+
+// browserConn : 브라우저 하나(로컬 프로세스 또는 원격 연결)
+type browserConn struct {
+	allocCancel   context.CancelFunc
+	browserCtx    context.Context
+	browserCancel context.CancelFunc
+}
+
+func (b *browserConn) close() {
+	b.browserCancel()
+	b.allocCancel()
+}
+
+// localEndpoint : 로컬 크롬을 가리키는 내부 키
+const localEndpoint = "local"
+
+// ChromedpPool : 브라우저를 띄워 두고 탭을 chromedp.pool_size 개까지 빌려주는 풀.
+// 탭마다 별도 browser context(시크릿 창과 비슷)를 써서 쿠키가 섞이지 않는다.
+// chromedp.remote_urls(ws://host:9222/... 또는 http://host:9222)를 지정하면
+// 로컬 크롬을 띄우지 않고 라운드로빈으로 원격 브라우저에 탭을 연다.
+type ChromedpPool struct {
+	cfg       *Config
+	allocOpts []chromedp.ExecAllocatorOption
+	remotes   *Endpoints // 원격 모드일 때만
+
+	lifeCtx    context.Context
+	lifeCancel context.CancelFunc
+
+	idle  chan *Tab
+	slots chan struct{} // 생성된 탭 수 제한
+
+	mu       sync.Mutex
+	browsers map[string]*browserConn
+	closed   bool
+}
+
+func NewChromedpPool(cfg *Config) *ChromedpPool {
+	allocOpts := append(chromedp.DefaultExecAllocatorOptions[:],
+		chromedp.UserAgent(cfg.Browser.UserAgent),
+		chromedp.WindowSize(cfg.Browser.WindowWidth, cfg.Browser.WindowHeight),
+		chromedp.Flag("lang", cfg.Browser.Lang),
+	)
+	if cfg.Chromedp.ExecPath != "" {
+		allocOpts = append(allocOpts, chromedp.ExecPath(cfg.Chromedp.ExecPath))
+	}
+
+	lifeCtx, lifeCancel := context.WithCancel(context.Background())
+	p := &ChromedpPool{
+		cfg:        cfg,
+		allocOpts:  allocOpts,
+		lifeCtx:    lifeCtx,
+		lifeCancel: lifeCancel,
+		idle:       make(chan *Tab, cfg.Chromedp.PoolSize),
+		slots:      make(chan struct{}, cfg.Chromedp.PoolSize),
+		browsers:   map[string]*browserConn{},
+	}
+	if remotes := cfg.Chromedp.RemoteURLs; len(remotes) > 0 {
+		p.remotes = NewEndpoints(remotes, CheckDevTools)
+		go p.remotes.Run(lifeCtx, cfg.Timeouts.HealthCheck)
+		slog.Info("🌍 chromedp using remote browsers", "remotes", remotes)
+	}
+	return p
+}
+
+// Warm : 탭을 미리 모두 열어 둔다. 첫 요청의 콜드 스타트를 줄이기 위해 시작 시 호출한다.
+func (p *ChromedpPool) Warm(ctx context.Context) error {
+	var tabs []*Tab
+	defer func() {
+		// 아직 아무 페이지도 열지 않은 탭이라 새로 만들지 않고 그대로 돌려준다
+		for _, t := range tabs {
+			p.put(t)
+		}
+	}()
+	for i := 0; i < cap(p.slots); i++ {
+		t, err := p.Lease(ctx)
+		if err != nil {
+			return err
+		}
+		tabs = append(tabs, t)
+	}
+	slog.Info("🔥 chromedp pool warmed", "tabs", len(tabs))
+	return nil
+}
+
+// Lease : 쉬고 있는 탭을 빌린다. 없으면 새로 열고, 한도에 걸리면 ctx가 끝날 때까지 기다린다.
+func (p *ChromedpPool) Lease(ctx context.Context) (*Tab, error) {
+	if p.isClosed() {
+		return nil, ErrPoolClosed
+	}
+
+	select {
+	case t := <-p.idle:
+		return t, nil
+	default:
+	}
+
+	select {
+	case t := <-p.idle:
+		return t, nil
+	case p.slots <- struct{}{}:
+		t, err := p.newTab()
+		if err != nil {
+			<-p.slots
+			return nil, err
+		}
+		return t, nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// TryLease : 기다리지 않고 탭을 빌린다. 탭이 모두 일하는 중이면 (nil, nil)
+func (p *ChromedpPool) TryLease() (*Tab, error) {
+	if p.isClosed() {
+		return nil, ErrPoolClosed
+	}
+	select {
+	case t := <-p.idle:
+		return t, nil
+	default:
+	}
+	select {
+	case t := <-p.idle:
+		return t, nil
+	case p.slots <- struct{}{}:
+		t, err := p.newTab()
+		if err != nil {
+			<-p.slots
+			return nil, err
+		}
+		return t, nil
+	default:
+		return nil, nil
+	}
+}
+
+// Alive : 탭을 빌리지 않고 열려 있는 브라우저 연결 중 하나라도 응답하는지 본다.
+func (p *ChromedpPool) Alive(ctx context.Context) error {
+	p.mu.Lock()
+	conns := make([]*browserConn, 0, len(p.browsers))
+	for _, b := range p.browsers {
+		conns = append(conns, b)
+	}
+	p.mu.Unlock()
+
+	var err error = NewError(CodeUnavailable, "no browser connected")
+	for _, b := range conns {
+		c := chromedp.FromContext(b.browserCtx)
+		if b.browserCtx.Err() != nil || c == nil || c.Browser == nil {
+			continue
+		}
+		if _, _, _, _, _, err = browser.GetVersion().Do(cdp.WithExecutor(ctx, c.Browser)); err == nil {
+			return nil
+		}
+	}
+	return err
+}
+
+// Release : 탭을 browser context째로 닫고 같은 자리에 새 탭을 열어 풀에 돌려준다.
+// 쿠키와 모든 origin(iframe, 리다이렉트 중간 페이지 포함)의 스토리지가 context와 함께 사라져서
+// 다음 요청에 남지 않는다. 새 탭은 요청을 붙잡지 않게 백그라운드에서 연다.
+func (p *ChromedpPool) Release(t *Tab) {
+	t.cancel()
+	if p.isClosed() {
+		<-p.slots
+		return
+	}
+	go func() {
+		fresh, err := p.newTab()
+		if err != nil {
+			slog.Warn("⚠️ Failed to reopen tab", "err", err)
+			<-p.slots
+			return
+		}
+		p.put(fresh)
+	}()
+}
+
+// put : 쓰지 않은 탭을 풀에 넣는다. 풀이 닫혔거나 가득 차면 닫는다.
+func (p *ChromedpPool) put(t *Tab) {
+	if p.isClosed() {
+		p.Discard(t)
+		return
+	}
+	select {
+	case p.idle <- t:
+	default:
+		p.Discard(t)
+	}
+}
+
+// InUse : 빌려 간 탭 수
+func (p *ChromedpPool) InUse() int { return len(p.slots) - len(p.idle) }
+
+// Open : 열려 있는 탭 수 (쉬는 탭 포함)
+func (p *ChromedpPool) Open() int { return len(p.slots) }
+
+// Discard : 망가진 탭을 풀에 돌려주지 않고 닫는다.
+func (p *ChromedpPool) Discard(t *Tab) {
+	t.cancel()
+	<-p.slots
+}
+
+// Close : 빌려준 탭까지 모든 탭과 브라우저 프로세스(원격이면 연결)를 종료한다.
+func (p *ChromedpPool) Close() {
+	p.mu.Lock()
+	if p.closed {
+		p.mu.Unlock()
+		return
+	}
+	p.closed = true
+	p.mu.Unlock()
+	p.lifeCancel()
+
+	for {
+		select {
+		case t := <-p.idle:
+			t.cancel()
+		default:
+			p.mu.Lock()
+			for key, b := range p.browsers {
+				b.close()
+				delete(p.browsers, key)
+			}
+			p.mu.Unlock()
+			slog.Info("🧹 chromedp pool closed")
+			return
+		}
+	}
+}
+
+func (p *ChromedpPool) isClosed() bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.closed
+}
+
+// newTab : 탭을 연다. 원격 모드에서는 실패한 주소를 제외하고 다음 주소로 다시 시도한다.
+func (p *ChromedpPool) newTab() (*Tab, error) {
+	if p.remotes == nil {
+		return p.newTabOn(localEndpoint)
+	}
+	var lastErr error
+	for i := 0; i < p.remotes.Len(); i++ {
+		endpoint, err := p.remotes.Next()
+		if err != nil {
+			return nil, err
+		}
+		t, err := p.newTabOn(endpoint)
+		if err == nil {
+			return t, nil
+		}
+		lastErr = err
+		p.remotes.MarkDown(endpoint)
+	}
+	return nil, lastErr
+}
+
+func (p *ChromedpPool) newTabOn(endpoint string) (*Tab, error) {
+	b, err := p.browser(endpoint)
+	if err != nil {
+		return nil, err
+	}
+	// 원격 브라우저에는 실행 플래그를 줄 수 없으니 UA/언어/창 크기는 탭 단위로 덮어쓴다.
+	ctx, cancel := chromedp.NewContext(b.browserCtx, chromedp.WithNewBrowserContext())
+	err = chromedp.Run(ctx,
+		emulation.SetUserAgentOverride(p.cfg.Browser.UserAgent).WithAcceptLanguage(p.cfg.Browser.Lang),
+		chromedp.EmulateViewport(int64(p.cfg.Browser.WindowWidth), int64(p.cfg.Browser.WindowHeight)),
+	)
+	if err != nil {
+		cancel()
+		return nil, err
+	}
+	return &Tab{ctx: ctx, cancel: cancel}, nil
+}
+
+// browser : endpoint의 브라우저 연결을 돌려준다. 없거나 끊겼으면 새로 만든다.
+func (p *ChromedpPool) browser(endpoint string) (*browserConn, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if b, ok := p.browsers[endpoint]; ok {
+		if b.browserCtx.Err() == nil {
+			return b, nil
+		}
+		b.close()
+		delete(p.browsers, endpoint)
+	}
+
+	var (
+		allocCtx    context.Context
+		allocCancel context.CancelFunc
+	)
+	if endpoint == localEndpoint {
+		allocCtx, allocCancel = chromedp.NewExecAllocator(p.lifeCtx, p.allocOpts...)
+	} else {
+		allocCtx, allocCancel = chromedp.NewRemoteAllocator(p.lifeCtx, endpoint)
+	}
+	browserCtx, browserCancel := chromedp.NewContext(allocCtx)
+	b := &browserConn{allocCancel: allocCancel, browserCtx: browserCtx, browserCancel: browserCancel}
+
+	// 첫 Run에서 로컬 브라우저 프로세스가 뜨거나 원격 브라우저에 연결된다.
+	if err := chromedp.Run(browserCtx); err != nil {
+		b.close()
+		return nil, err
+	}
+	p.browsers[endpoint] = b
+	return b, nil
+}
@@ -0,0 +1,116 @@
+package internal
+
+import (
+	"context"
+	"expvar"
+	"sync"
+	"time"
+)
+
+// coalescedCalls : 진행 중인 요청에 합쳐진 호출 수 (종류별). /debug/vars 의 scrape_coalesced
+var coalescedCalls = expvar.NewMap("scrape_coalesced")
+
+// flightGroup : 같은 키로 동시에 들어온 스크래핑을 하나로 합친다.
+// 스크래핑은 특정 요청의 마감 시간을 따르지 않고, 기다리는 요청이 모두 떠나거나 max가 지나야 취소된다.
+// 그래서 먼저 온 요청의 ?timeout= 이 짧아도 나중에 붙은 요청은 자기 마감 시간까지 기다릴 수 있다.
+// 브라우저 사용 시간은 스크래핑이 끝났을 때 기다리던 요청들이 똑같이 나눠서 각자의 BrowserMeter에 더한다.
+type flightGroup struct {
+	max   time.Duration // 합쳐진 스크래핑 하나의 최대 시간 (timeouts.max). 0이면 제한 없음
+	mu    sync.Mutex
+	calls map[string]*flight
+}
+
+type flight struct {
+	done   chan struct{}
+	val    any
+	err    error
+	refs   int  // 결과를 기다리는 요청 수
+	ended  bool // fn이 끝남. 이 뒤로 refs는 바뀌지 않는다
+	meter  *BrowserMeter
+	cancel context.CancelFunc
+}
+
+// share : 끝난 스크래핑의 브라우저 사용 시간 중 요청 하나의 몫. g.mu를 잡은 채로 부른다.
+func (f *flight) share() time.Duration {
+	if f.refs == 0 {
+		return 0
+	}
+	return f.meter.Elapsed() / time.Duration(f.refs)
+}
+
+// Do : key로 진행 중인 호출이 있으면 그 결과를 기다리고(shared=true), 없으면 fn을 실행한다.
+func (g *flightGroup) Do(ctx context.Context, key string, fn func(context.Context) (any, error)) (v any, shared bool, err error) {
+	g.mu.Lock()
+	if f, ok := g.calls[key]; ok {
+		f.refs++
+		g.mu.Unlock()
+		v, err = g.wait(ctx, key, f)
+		return v, true, err
+	}
+
+	// 요청 값(no-cache 등)은 넘기고 취소와 마감 시간은 끊는다.
+	// 브라우저 시간은 먼저 온 요청의 미터 대신 이 호출의 미터에 모은다
+	meter := &BrowserMeter{}
+	base := WithBrowserMeter(context.WithoutCancel(ctx), meter)
+	fctx, cancel := context.WithCancel(base)
+	if g.max > 0 {
+		fctx, cancel = context.WithTimeout(base, g.max)
+	}
+	f := &flight{done: make(chan struct{}), refs: 1, meter: meter, cancel: cancel}
+	if g.calls == nil {
+		g.calls = map[string]*flight{}
+	}
+	g.calls[key] = f
+	g.mu.Unlock()
+
+	go func() {
+		defer cancel()
+		f.val, f.err = fn(fctx)
+		g.mu.Lock()
+		f.ended = true
+		g.forget(key, f)
+		g.mu.Unlock()
+		close(f.done)
+	}()
+	v, err = g.wait(ctx, key, f)
+	return v, false, err
+}
+
+// wait : 결과를 기다린다. 먼저 떠나는 요청이 마지막이면 스크래핑을 취소한다.
+// refs가 0이 되는 순간 같은 잠금 안에서 키를 빼야 그 사이에 새 요청이 붙었다가 같이 취소되지 않는다.
+func (g *flightGroup) wait(ctx context.Context, key string, f *flight) (any, error) {
+	select {
+	case <-f.done:
+		g.mu.Lock()
+		share := f.share()
+		g.mu.Unlock()
+		addBrowserTime(ctx, share)
+		return f.val, f.err
+	case <-ctx.Done():
+		g.mu.Lock()
+		if f.ended {
+			// 결과가 나온 뒤에 떠나도 몫은 낸다
+			share := f.share()
+			g.mu.Unlock()
+			addBrowserTime(ctx, share)
+			return nil, ctx.Err()
+		}
+		f.refs--
+		last := f.refs == 0
+		if last {
+			g.forget(key, f)
+		}
+		g.mu.Unlock()
+		if last {
+			f.cancel()
+		}
+		return nil, ctx.Err()
+	}
+}
+
+// forget : 끝났거나 취소된 호출에 새 요청이 붙지 않게 뺀다. g.mu를 잡은 채로 부른다.
+func (g *flightGroup) forget(key string, f *flight) {
+	if g.calls[key] == f {
+		delete(g.calls, key)
+	}
+}
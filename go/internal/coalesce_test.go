@@ -0,0 +1,212 @@
+package internal
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestFlightGroupSharesResult(t *testing.T) {
+	var g flightGroup
+	var calls atomic.Int32
+	release := make(chan struct{})
+	fn := func(ctx context.Context) (any, error) {
+		calls.Add(1)
+		<-release
+		return "result", nil
+	}
+
+	const n = 5
+	var wg sync.WaitGroup
+	var sharedCount atomic.Int32
+	for i := 0; i < n; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			v, shared, err := g.Do(context.Background(), "key", fn)
+			if err != nil || v != "result" {
+				t.Errorf("got %v, %v", v, err)
+			}
+			if shared {
+				sharedCount.Add(1)
+			}
+		}()
+	}
+	// 모두 붙을 때까지 기다렸다가 결과를 낸다
+	for {
+		g.mu.Lock()
+		f := g.calls["key"]
+		joined := f != nil && f.refs == n
+		g.mu.Unlock()
+		if joined {
+			break
+		}
+		time.Sleep(time.Millisecond)
+	}
+	close(release)
+	wg.Wait()
+
+	if calls.Load() != 1 || sharedCount.Load() != n-1 {
+		t.Errorf("calls = %d, shared = %d", calls.Load(), sharedCount.Load())
+	}
+}
+
+func TestFlightGroupCancelsWhenAllWaitersLeave(t *testing.T) {
+	var g flightGroup
+	started := make(chan struct{})
+	canceled := make(chan struct{})
+	fn := func(ctx context.Context) (any, error) {
+		close(started)
+		<-ctx.Done()
+		close(canceled)
+		return nil, ctx.Err()
+	}
+
+	first, cancelFirst := context.WithCancel(context.Background())
+	second, cancelSecond := context.WithCancel(context.Background())
+	errs := make(chan error, 2)
+	go func() { _, _, err := g.Do(first, "key", fn); errs <- err }()
+	<-started
+	go func() { _, _, err := g.Do(second, "key", fn); errs <- err }()
+	for {
+		g.mu.Lock()
+		refs := g.calls["key"].refs
+		g.mu.Unlock()
+		if refs == 2 {
+			break
+		}
+		time.Sleep(time.Millisecond)
+	}
+
+	cancelFirst()
+	<-errs
+	select {
+	case <-canceled:
+		t.Fatal("scrape canceled while a waiter remained")
+	case <-time.After(20 * time.Millisecond):
+	}
+
+	cancelSecond()
+	<-errs
+	select {
+	case <-canceled:
+	case <-time.After(time.Second):
+		t.Fatal("scrape not canceled after all waiters left")
+	}
+}
+
+func TestFlightGroupOutlivesShortLeaderDeadline(t *testing.T) {
+	g := flightGroup{max: time.Second}
+	started := make(chan struct{})
+	release := make(chan struct{})
+	fn := func(ctx context.Context) (any, error) {
+		close(started)
+		select {
+		case <-release:
+			return "ok", nil
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+
+	leader, cancelLeader := context.WithTimeout(context.Background(), 200*time.Millisecond)
+	defer cancelLeader()
+	leaderErr := make(chan error, 1)
+	go func() { _, _, err := g.Do(leader, "key", fn); leaderErr <- err }()
+	<-started
+
+	follower, cancelFollower := context.WithTimeout(context.Background(), time.Second)
+	defer cancelFollower()
+	result := make(chan any, 1)
+	go func() { v, _, _ := g.Do(follower, "key", fn); result <- v }()
+	for {
+		g.mu.Lock()
+		refs := g.calls["key"].refs
+		g.mu.Unlock()
+		if refs == 2 {
+			break
+		}
+		time.Sleep(time.Millisecond)
+	}
+
+	if err := <-leaderErr; !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("leader err = %v", err)
+	}
+	close(release)
+	if v := <-result; v != "ok" {
+		t.Errorf("follower got %v after leader's deadline", v)
+	}
+}
+
+func TestFlightGroupNewCallerAfterLastWaiterLeaves(t *testing.T) {
+	var g flightGroup
+	started := make(chan struct{}, 2)
+	fn := func(ctx context.Context) (any, error) {
+		started <- struct{}{}
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(50 * time.Millisecond):
+			return "ok", nil
+		}
+	}
+
+	first, cancelFirst := context.WithCancel(context.Background())
+	errs := make(chan error, 1)
+	go func() { _, _, err := g.Do(first, "key", fn); errs <- err }()
+	<-started
+	cancelFirst()
+	<-errs
+
+	// 마지막 요청이 떠나면 키가 바로 빠져서 새 요청은 취소된 호출에 붙지 않는다
+	g.mu.Lock()
+	_, stale := g.calls["key"]
+	g.mu.Unlock()
+	if stale {
+		t.Fatal("canceled flight still registered")
+	}
+	if v, shared, err := g.Do(context.Background(), "key", fn); err != nil || shared || v != "ok" {
+		t.Errorf("got %v, shared=%v, %v", v, shared, err)
+	}
+}
+
+func TestFlightGroupSplitsBrowserTime(t *testing.T) {
+	var g flightGroup
+	started := make(chan struct{})
+	release := make(chan struct{})
+	fn := func(ctx context.Context) (any, error) {
+		stop := meterBrowser(ctx)
+		close(started)
+		<-release
+		time.Sleep(20 * time.Millisecond)
+		stop()
+		return "ok", nil
+	}
+
+	leaderMeter, followerMeter := &BrowserMeter{}, &BrowserMeter{}
+	done := make(chan struct{}, 2)
+	go func() { g.Do(WithBrowserMeter(context.Background(), leaderMeter), "key", fn); done <- struct{}{} }()
+	<-started
+	go func() { g.Do(WithBrowserMeter(context.Background(), followerMeter), "key", fn); done <- struct{}{} }()
+	for {
+		g.mu.Lock()
+		refs := g.calls["key"].refs
+		g.mu.Unlock()
+		if refs == 2 {
+			break
+		}
+		time.Sleep(time.Millisecond)
+	}
+	close(release)
+	<-done
+	<-done
+
+	// 먼저 온 요청이 다 내지 않고 기다린 요청들이 반씩 낸다
+	l, f := leaderMeter.Elapsed(), followerMeter.Elapsed()
+	if l < 10*time.Millisecond || l != f {
+		t.Errorf("leader = %v, follower = %v", l, f)
+	}
+}
@@ -0,0 +1,473 @@
+package internal
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"log/slog"
+	"os"
+	"runtime"
+	"strconv"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Config : 서버 전체 설정. 기본값 → 설정 파일(YAML) → 환경변수 → 플래그 순으로 덮어쓴다.
+type Config struct {
+	Addr    string      `yaml:"addr"`    // 서버 주소
+	Engine  string      `yaml:"engine"`  // 기본 브라우저 엔진 (chromedp, selenium)
+	Chain   []string    `yaml:"chain"`   // 엔진 시도 순서. 비어 있으면 http → engine → 나머지
+	Domains []ChainRule `yaml:"domains"` // 도메인별 엔진 순서
+
+	Browser   BrowserConfig   `yaml:"browser"`
+	Chromedp  ChromedpConfig  `yaml:"chromedp"`
+	Selenium  SeleniumConfig  `yaml:"selenium"`
+	Timeouts  TimeoutConfig   `yaml:"timeouts"`
+	Batch     BatchConfig     `yaml:"batch"`
+	Jobs      JobsConfig      `yaml:"jobs"`
+	Cache     CacheConfig     `yaml:"cache"`
+	RateLimit RateLimitConfig `yaml:"rate_limit"`
+	Auth      AuthConfig      `yaml:"auth"`
+	Log       LogConfig       `yaml:"log"`
+	Tracing   TracingConfig   `yaml:"tracing"`
+	Thread    ThreadConfig    `yaml:"thread"`
+}
+
+// BrowserConfig : 두 브라우저 엔진이 공유하는 크롬 옵션
+type BrowserConfig struct {
+	UserAgent    string `yaml:"user_agent"`
+	Lang         string `yaml:"lang"`
+	WindowWidth  int    `yaml:"window_width"`
+	WindowHeight int    `yaml:"window_height"`
+}
+
+type ChromedpConfig struct {
+	ExecPath   string   `yaml:"exec_path"` // 비어 있으면 chromedp가 크롬을 찾는다
+	PoolSize   int      `yaml:"pool_size"`
+	RemoteURLs []string `yaml:"remote_urls"`
+}
+
+type SeleniumConfig struct {
+	ChromeDriverPath string   `yaml:"chromedriver_path"`
+	ChromiumPath     string   `yaml:"chromium_path"`
+	Port             int      `yaml:"port"` // 로컬 ChromeDriver 포트
+	PoolSize         int      `yaml:"pool_size"`
+	MaxUses          int      `yaml:"max_uses"`
+	HubURLs          []string `yaml:"hub_urls"`
+}
+
+type TimeoutConfig struct {
+	PageLoad    time.Duration `yaml:"page_load"`    // selenium 페이지 로딩/요소 대기, chromedp 본문 렌더링 대기
+	Meta        time.Duration `yaml:"meta"`         // chromedp 메타 스크래핑 전체
+	Tweet       time.Duration `yaml:"tweet"`        // chromedp 트윗 스크래핑 전체
+	HTTP        time.Duration `yaml:"http"`         // http 엔진 요청
+	HealthCheck time.Duration `yaml:"health_check"` // 원격 브라우저 헬스체크 주기
+	Shutdown    time.Duration `yaml:"shutdown"`     // 종료 시 처리 중인 요청을 기다리는 시간
+	Max         time.Duration `yaml:"max"`          // 요청의 ?timeout= 상한
+	Ready       time.Duration `yaml:"ready"`        // /readyz 에서 엔진 하나를 확인하는 시간
+}
+
+// CacheConfig : 결과 캐시 설정
+type CacheConfig struct {
+	Size     int           `yaml:"size"`      // 메모리에 둘 최대 결과 수. 0이면 메모리 캐시를 끈다
+	Dir      string        `yaml:"dir"`       // 디스크 캐시 위치. 비어 있으면 메모리만 쓴다
+	TweetTTL time.Duration `yaml:"tweet_ttl"` // 트윗 결과 보관 기간. 0이면 캐시하지 않음
+	MetaTTL  time.Duration `yaml:"meta_ttl"`  // 메타 결과 보관 기간. 0이면 캐시하지 않음
+}
+
+// LogConfig : 로그 설정
+type LogConfig struct {
+	Level  string `yaml:"level"`  // debug | info | warn | error
+	Format string `yaml:"format"` // json | text
+	Fields bool   `yaml:"fields"` // 스크래핑한 필드 값(제목, 설명 ...)을 debug 로그로 남김. 운영에서는 끈다
+}
+
+// ThreadConfig : /scrape-twitter?thread=1 로 모을 작성자 답글 수
+type ThreadConfig struct {
+	Depth    int `yaml:"depth"`     // ?depth= 가 없을 때
+	MaxDepth int `yaml:"max_depth"` // ?depth= 상한
+}
+
+// TracingConfig : OpenTelemetry 트레이싱
+type TracingConfig struct {
+	Exporter    string  `yaml:"exporter"`     // none | otlp | stdout
+	Endpoint    string  `yaml:"endpoint"`     // OTLP/HTTP 수집기 주소. 비어 있으면 OTEL_EXPORTER_OTLP_ENDPOINT 또는 http://localhost:4318
+	ServiceName string  `yaml:"service_name"` // service.name 리소스 속성
+	SampleRatio float64 `yaml:"sample_ratio"` // 새로 시작하는 트레이스 중 남길 비율 (0~1)
+}
+
+// RateLimitConfig : 대상 도메인별 요청 제한. 규칙에 없는 호스트는 호스트마다 default를 따로 적용한다.
+type RateLimitConfig struct {
+	Default DomainLimit   `yaml:"default"`
+	Domains []DomainLimit `yaml:"domains"`
+}
+
+// DomainLimit : 0이면 그 항목은 제한하지 않는다.
+type DomainLimit struct {
+	Pattern     string  `yaml:"pattern"`
+	RPS         float64 `yaml:"rps"`         // 초당 스크래핑 시작 수
+	Burst       int     `yaml:"burst"`       // 한 번에 몰아서 시작할 수 있는 수. 기본 1
+	Concurrency int     `yaml:"concurrency"` // 동시에 스크래핑할 수 있는 수
+	Queue       int     `yaml:"queue"`       // 자리를 기다릴 수 있는 요청 수. 규칙에서 0이면 default 값
+}
+
+// AuthConfig : API 키 인증. keys와 keys_file의 키를 합쳐 쓰고, 하나도 없으면 인증을 끈다.
+type AuthConfig struct {
+	Header   string        `yaml:"header"`    // 키를 실을 헤더. Authorization: Bearer <key> 도 받는다
+	Keys     []APIKey      `yaml:"keys"`      // 설정 파일에 직접 적은 키
+	KeysFile string        `yaml:"keys_file"` // keys와 같은 모양의 YAML 목록 파일
+	Window   time.Duration `yaml:"window"`    // 할당량을 초기화하는 주기
+}
+
+// BatchConfig : POST /scrape/batch 설정
+type BatchConfig struct {
+	Concurrency int `yaml:"concurrency"` // 배치 하나에서 동시에 스크래핑할 URL 수
+	MaxItems    int `yaml:"max_items"`   // 배치 하나에 넣을 수 있는 최대 URL 수
+}
+
+// JobsConfig : 비동기 작업(POST /jobs) 설정
+type JobsConfig struct {
+	Workers   int           `yaml:"workers"`    // 동시에 처리할 작업 수
+	QueueSize int           `yaml:"queue_size"` // 대기할 수 있는 최대 작업 수
+	TTL       time.Duration `yaml:"ttl"`        // 끝난 작업을 조회할 수 있는 기간
+}
+
+// DefaultConfig : 설정하지 않았을 때의 값. macOS(로컬 개발)는 homebrew 경로를 쓴다.
+func DefaultConfig() *Config {
+	cfg := &Config{
+		Addr:   ":18081",
+		Engine: "chromedp",
+		// 자바스크립트로 그려지는 사이트는 http 엔진을 건너뛰고 바로 브라우저로
+		Domains: []ChainRule{
+			{Pattern: "x.com", Engines: []string{"chromedp", "selenium"}},
+			{Pattern: "*.notion.site", Engines: []string{"chromedp", "selenium"}},
+		},
+		Browser: BrowserConfig{
+			UserAgent:    "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/121.0.0.0 Safari/537.36",
+			Lang:         "ko-KR,ko",
+			WindowWidth:  1280,
+			WindowHeight: 1024,
+		},
+		Chromedp: ChromedpConfig{PoolSize: 4},
+		Selenium: SeleniumConfig{
+			ChromeDriverPath: "/usr/bin/chromedriver",
+			ChromiumPath:     "/usr/bin/chromium",
+			Port:             9515,
+			PoolSize:         2,
+			MaxUses:          50,
+		},
+		Timeouts: TimeoutConfig{
+			PageLoad:    10 * time.Second,
+			Meta:        15 * time.Second,
+			Tweet:       25 * time.Second,
+			HTTP:        10 * time.Second,
+			HealthCheck: 10 * time.Second,
+			Shutdown:    30 * time.Second,
+			Max:         60 * time.Second,
+			Ready:       10 * time.Second,
+		},
+		Batch: BatchConfig{Concurrency: 4, MaxItems: 100},
+		Jobs:  JobsConfig{Workers: 4, QueueSize: 1000, TTL: time.Hour},
+		Cache: CacheConfig{Size: 1000, TweetTTL: time.Hour, MetaTTL: 6 * time.Hour},
+		Auth:  AuthConfig{Header: "X-API-Key", Window: 24 * time.Hour},
+		Log:   LogConfig{Level: "info", Format: "json"},
+		RateLimit: RateLimitConfig{
+			Default: DomainLimit{Concurrency: 4, Queue: 20},
+			Domains: []DomainLimit{
+				{Pattern: "x.com", RPS: 1, Burst: 2, Concurrency: 2},
+				{Pattern: "notion.site", RPS: 2, Burst: 2, Concurrency: 2},
+			},
+		},
+		Tracing: TracingConfig{Exporter: "none", ServiceName: "cmsn-scraper", SampleRatio: 1},
+		Thread:  ThreadConfig{Depth: 10, MaxDepth: 25},
+	}
+	if runtime.GOOS == "darwin" {
+		cfg.Selenium.ChromeDriverPath = "/opt/homebrew/bin/chromedriver"
+		cfg.Selenium.ChromiumPath = "/opt/homebrew/bin/chromium"
+	}
+	return cfg
+}
+
+// LoadConfig : args(os.Args[1:])와 환경변수로 설정을 읽고 검사한다.
+// 설정 파일 경로는 -config 플래그 또는 SCRAPER_CONFIG 환경변수로 지정한다.
+func LoadConfig(args []string) (*Config, error) {
+	cfg := DefaultConfig()
+
+	var (
+		path                  string
+		addr, engine          string
+		chromedpSize          int
+		seleniumSize          int
+		pageLoad, httpTimeout time.Duration
+	)
+	fs := flag.NewFlagSet("server", flag.ContinueOnError)
+	fs.StringVar(&path, "config", os.Getenv("SCRAPER_CONFIG"), "YAML 설정 파일 경로")
+	fs.StringVar(&addr, "addr", "", "서버 주소 (예: :18081)")
+	fs.StringVar(&engine, "engine", "", "기본 브라우저 엔진 (chromedp, selenium)")
+	fs.IntVar(&chromedpSize, "chromedp-pool-size", 0, "chromedp 탭 수")
+	fs.IntVar(&seleniumSize, "selenium-pool-size", 0, "selenium 세션 수")
+	fs.DurationVar(&pageLoad, "timeout-page-load", 0, "selenium 페이지 로딩 대기 시간")
+	fs.DurationVar(&httpTimeout, "timeout-http", 0, "http 엔진 요청 제한 시간")
+	if err := fs.Parse(args); err != nil {
+		return nil, err
+	}
+
+	if path != "" {
+		raw, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("read config: %w", err)
+		}
+		if err := yaml.Unmarshal(raw, cfg); err != nil {
+			return nil, fmt.Errorf("parse config %s: %w", path, err)
+		}
+	}
+
+	if err := cfg.applyEnv(os.LookupEnv); err != nil {
+		return nil, err
+	}
+
+	// 명시적으로 넘긴 플래그만 덮어쓴다
+	fs.Visit(func(f *flag.Flag) {
+		switch f.Name {
+		case "addr":
+			cfg.Addr = addr
+		case "engine":
+			cfg.Engine = engine
+		case "chromedp-pool-size":
+			cfg.Chromedp.PoolSize = chromedpSize
+		case "selenium-pool-size":
+			cfg.Selenium.PoolSize = seleniumSize
+		case "timeout-page-load":
+			cfg.Timeouts.PageLoad = pageLoad
+		case "timeout-http":
+			cfg.Timeouts.HTTP = httpTimeout
+		}
+	})
+
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+	return cfg, nil
+}
+
+// applyEnv : 환경변수 값으로 덮어쓴다. 기존 환경변수 이름(SCRAPER_ENGINE 등)을 그대로 쓴다.
+func (c *Config) applyEnv(lookup func(string) (string, bool)) error {
+	str := func(dst *string) func(string) error {
+		return func(v string) error { *dst = v; return nil }
+	}
+	num := func(dst *int) func(string) error {
+		return func(v string) error {
+			n, err := strconv.Atoi(v)
+			*dst = n
+			return err
+		}
+	}
+	float := func(dst *float64) func(string) error {
+		return func(v string) error {
+			f, err := strconv.ParseFloat(v, 64)
+			*dst = f
+			return err
+		}
+	}
+	dur := func(dst *time.Duration) func(string) error {
+		return func(v string) error {
+			d, err := time.ParseDuration(v)
+			*dst = d
+			return err
+		}
+	}
+	boolean := func(dst *bool) func(string) error {
+		return func(v string) error {
+			b, err := strconv.ParseBool(v)
+			*dst = b
+			return err
+		}
+	}
+	list := func(dst *[]string) func(string) error {
+		return func(v string) error { *dst = SplitList(v); return nil }
+	}
+
+	vars := []struct {
+		name  string
+		apply func(string) error
+	}{
+		{"SCRAPER_ADDR", str(&c.Addr)},
+		{"SCRAPER_ENGINE", str(&c.Engine)},
+		{"SCRAPER_CHAIN", list(&c.Chain)},
+		{"SCRAPER_DOMAIN_ENGINES", func(v string) error {
+			c.Domains = ParseDomainRules(v)
+			return nil
+		}},
+		{"SCRAPER_USER_AGENT", str(&c.Browser.UserAgent)},
+		{"SCRAPER_LANG", str(&c.Browser.Lang)},
+		{"SCRAPER_WINDOW_SIZE", func(v string) error {
+			w, h, ok := strings.Cut(v, "x")
+			if !ok {
+				return errors.New("expected WIDTHxHEIGHT")
+			}
+			var err error
+			if c.Browser.WindowWidth, err = strconv.Atoi(w); err != nil {
+				return err
+			}
+			c.Browser.WindowHeight, err = strconv.Atoi(h)
+			return err
+		}},
+		{"CHROMEDP_EXEC_PATH", str(&c.Chromedp.ExecPath)},
+		{"CHROMEDP_POOL_SIZE", num(&c.Chromedp.PoolSize)},
+		{"CHROMEDP_REMOTE_URLS", list(&c.Chromedp.RemoteURLs)},
+		{"CHROMEDRIVER_PATH", str(&c.Selenium.ChromeDriverPath)},
+		{"CHROMIUM_PATH", str(&c.Selenium.ChromiumPath)},
+		{"CHROMEDRIVER_PORT", num(&c.Selenium.Port)},
+		{"SELENIUM_POOL_SIZE", num(&c.Selenium.PoolSize)},
+		{"SELENIUM_MAX_USES", num(&c.Selenium.MaxUses)},
+		{"SELENIUM_HUB_URLS", list(&c.Selenium.HubURLs)},
+		{"TIMEOUT_PAGE_LOAD", dur(&c.Timeouts.PageLoad)},
+		{"TIMEOUT_META", dur(&c.Timeouts.Meta)},
+		{"TIMEOUT_TWEET", dur(&c.Timeouts.Tweet)},
+		{"TIMEOUT_HTTP", dur(&c.Timeouts.HTTP)},
+		{"HEALTH_CHECK_INTERVAL", dur(&c.Timeouts.HealthCheck)},
+		{"TIMEOUT_SHUTDOWN", dur(&c.Timeouts.Shutdown)},
+		{"TIMEOUT_MAX", dur(&c.Timeouts.Max)},
+		{"TIMEOUT_READY", dur(&c.Timeouts.Ready)},
+		{"BATCH_CONCURRENCY", num(&c.Batch.Concurrency)},
+		{"BATCH_MAX_ITEMS", num(&c.Batch.MaxItems)},
+		{"JOBS_WORKERS", num(&c.Jobs.Workers)},
+		{"JOBS_QUEUE_SIZE", num(&c.Jobs.QueueSize)},
+		{"JOBS_TTL", dur(&c.Jobs.TTL)},
+		{"CACHE_SIZE", num(&c.Cache.Size)},
+		{"CACHE_DIR", str(&c.Cache.Dir)},
+		{"CACHE_TWEET_TTL", dur(&c.Cache.TweetTTL)},
+		{"CACHE_META_TTL", dur(&c.Cache.MetaTTL)},
+		{"API_KEY_HEADER", str(&c.Auth.Header)},
+		{"API_KEYS_FILE", str(&c.Auth.KeysFile)},
+		{"API_QUOTA_WINDOW", dur(&c.Auth.Window)},
+		{"RATE_LIMIT_RPS", float(&c.RateLimit.Default.RPS)},
+		{"RATE_LIMIT_BURST", num(&c.RateLimit.Default.Burst)},
+		{"RATE_LIMIT_CONCURRENCY", num(&c.RateLimit.Default.Concurrency)},
+		{"RATE_LIMIT_QUEUE", num(&c.RateLimit.Default.Queue)},
+		{"LOG_LEVEL", str(&c.Log.Level)},
+		{"LOG_FORMAT", str(&c.Log.Format)},
+		{"LOG_FIELDS", boolean(&c.Log.Fields)},
+		{"TRACING_EXPORTER", str(&c.Tracing.Exporter)},
+		{"TRACING_ENDPOINT", str(&c.Tracing.Endpoint)},
+		{"TRACING_SERVICE_NAME", str(&c.Tracing.ServiceName)},
+		{"TRACING_SAMPLE_RATIO", float(&c.Tracing.SampleRatio)},
+		{"THREAD_DEPTH", num(&c.Thread.Depth)},
+		{"THREAD_MAX_DEPTH", num(&c.Thread.MaxDepth)},
+	}
+	for _, v := range vars {
+		val, ok := lookup(v.name)
+		if !ok || strings.TrimSpace(val) == "" {
+			continue
+		}
+		if err := v.apply(strings.TrimSpace(val)); err != nil {
+			return fmt.Errorf("invalid %s=%q: %w", v.name, val, err)
+		}
+	}
+	return nil
+}
+
+// Validate : 시작 시 설정 값을 검사한다. 문제를 모두 모아서 돌려준다.
+func (c *Config) Validate() error {
+	var errs []error
+	check := func(ok bool, format string, args ...any) {
+		if !ok {
+			errs = append(errs, fmt.Errorf(format, args...))
+		}
+	}
+
+	check(c.Addr != "", "addr is required")
+	check(c.Engine == "chromedp" || c.Engine == "selenium", "engine must be chromedp or selenium, got %q", c.Engine)
+	check(c.Browser.UserAgent != "", "browser.user_agent is required")
+	check(c.Browser.WindowWidth > 0 && c.Browser.WindowHeight > 0, "browser window size must be positive")
+	check(c.Chromedp.PoolSize > 0, "chromedp.pool_size must be positive")
+	check(c.Selenium.PoolSize > 0, "selenium.pool_size must be positive")
+	check(c.Selenium.MaxUses > 0, "selenium.max_uses must be positive")
+	check(c.Selenium.Port > 0 && c.Selenium.Port < 65536, "selenium.port out of range: %d", c.Selenium.Port)
+	check(len(c.Selenium.HubURLs) > 0 || c.Selenium.ChromeDriverPath != "", "selenium.chromedriver_path is required without hub_urls")
+	check(c.Timeouts.PageLoad > 0, "timeouts.page_load must be positive")
+	check(c.Timeouts.Meta > 0, "timeouts.meta must be positive")
+	check(c.Timeouts.Tweet > 0, "timeouts.tweet must be positive")
+	check(c.Timeouts.HTTP > 0, "timeouts.http must be positive")
+	check(c.Timeouts.HealthCheck > 0, "timeouts.health_check must be positive")
+	check(c.Timeouts.Shutdown > 0, "timeouts.shutdown must be positive")
+	check(c.Timeouts.Max > 0, "timeouts.max must be positive")
+	check(c.Timeouts.Ready > 0, "timeouts.ready must be positive")
+	check(c.Batch.Concurrency > 0, "batch.concurrency must be positive")
+	check(c.Batch.MaxItems > 0, "batch.max_items must be positive")
+	check(c.Jobs.Workers > 0, "jobs.workers must be positive")
+	check(c.Jobs.QueueSize > 0, "jobs.queue_size must be positive")
+	check(c.Jobs.TTL > 0, "jobs.ttl must be positive")
+	check(c.Cache.Size >= 0, "cache.size must not be negative")
+	check(c.Cache.TweetTTL >= 0 && c.Cache.MetaTTL >= 0, "cache ttls must not be negative")
+	for _, rule := range c.Domains {
+		check(rule.Pattern != "" && len(rule.Engines) > 0, "domain rule needs pattern and engines: %+v", rule)
+	}
+	check(c.Auth.Header != "", "auth.header is required")
+	check(c.Auth.Window > 0, "auth.window must be positive")
+	check(c.RateLimit.Default.Queue > 0, "rate_limit.default.queue must be positive")
+	for _, limit := range append([]DomainLimit{c.RateLimit.Default}, c.RateLimit.Domains...) {
+		check(limit.RPS >= 0 && limit.Burst >= 0 && limit.Concurrency >= 0 && limit.Queue >= 0,
+			"rate limit must not be negative: %+v", limit)
+	}
+	for _, limit := range c.RateLimit.Domains {
+		check(limit.Pattern != "", "rate_limit domain needs pattern: %+v", limit)
+	}
+
+	var level slog.Level
+	check(level.UnmarshalText([]byte(c.Log.Level)) == nil, "log.level must be debug, info, warn or error, got %q", c.Log.Level)
+	check(c.Log.Format == "json" || c.Log.Format == "text", "log.format must be json or text, got %q", c.Log.Format)
+	check(c.Tracing.Exporter == "none" || c.Tracing.Exporter == "otlp" || c.Tracing.Exporter == "stdout",
+		"tracing.exporter must be none, otlp or stdout, got %q", c.Tracing.Exporter)
+	check(c.Tracing.SampleRatio >= 0 && c.Tracing.SampleRatio <= 1, "tracing.sample_ratio must be between 0 and 1")
+	check(c.Tracing.ServiceName != "", "tracing.service_name is required")
+	check(c.Thread.Depth > 0 && c.Thread.MaxDepth >= c.Thread.Depth, "thread.depth must be positive and not above thread.max_depth")
+
+	if len(errs) > 0 {
+		return fmt.Errorf("invalid config: %w", errors.Join(errs...))
+	}
+	return nil
+}
+
+// ChainPolicy : 설정의 엔진 순서. chain이 비어 있으면 http → 기본 엔진 → 나머지 엔진 순.
+func (c *Config) ChainPolicy(engines []string) ChainPolicy {
+	policy := ChainPolicy{Default: c.Chain, Rules: c.Domains}
+	if len(policy.Default) == 0 {
+		policy.Default = []string{"http", c.Engine}
+		for _, name := range engines {
+			if name != "http" && name != c.Engine {
+				policy.Default = append(policy.Default, name)
+			}
+		}
+	}
+	return policy
+}
+
+// SplitList : 콤마로 구분된 값을 나눈다.
+func SplitList(v string) []string {
+	var out []string
+	for _, s := range strings.Split(v, ",") {
+		if s = strings.TrimSpace(s); s != "" {
+			out = append(out, s)
+		}
+	}
+	return out
+}
+
+// ParseDomainRules : "x.com=chromedp|selenium,notion.site=chromedp" 형태를 규칙 목록으로 바꾼다.
+func ParseDomainRules(v string) []ChainRule {
+	var rules []ChainRule
+	for _, pair := range SplitList(v) {
+		pattern, names, ok := strings.Cut(pair, "=")
+		if !ok {
+			continue
+		}
+		rules = append(rules, ChainRule{
+			Pattern: strings.TrimSpace(pattern),
+			Engines: strings.Split(strings.TrimSpace(names), "|"),
+		})
+	}
+	return rules
+}
@@ -0,0 +1,93 @@
+package internal
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestLoadConfigPrecedence(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	err := os.WriteFile(path, []byte(`
+addr: ":9000"
+engine: selenium
+domains:
+  - pattern: x.com
+    engines: [chromedp]
+chromedp:
+  pool_size: 8
+timeouts:
+  tweet: 40s
+`), 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Setenv("SCRAPER_CONFIG", path)
+	t.Setenv("CHROMEDP_POOL_SIZE", "6")
+	t.Setenv("SCRAPER_WINDOW_SIZE", "800x600")
+
+	cfg, err := LoadConfig([]string{"-chromedp-pool-size", "3"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.Addr != ":9000" || cfg.Engine != "selenium" {
+		t.Errorf("file values not applied: addr=%q engine=%q", cfg.Addr, cfg.Engine)
+	}
+	if cfg.Chromedp.PoolSize != 3 {
+		t.Errorf("flag should override env and file: pool_size=%d", cfg.Chromedp.PoolSize)
+	}
+	if cfg.Browser.WindowWidth != 800 || cfg.Browser.WindowHeight != 600 {
+		t.Errorf("window size = %dx%d", cfg.Browser.WindowWidth, cfg.Browser.WindowHeight)
+	}
+	if cfg.Timeouts.Tweet != 40*time.Second || cfg.Timeouts.Meta != 15*time.Second {
+		t.Errorf("timeouts = %+v", cfg.Timeouts)
+	}
+	if len(cfg.Domains) != 1 || cfg.Domains[0].Engines[0] != "chromedp" {
+		t.Errorf("domains = %+v", cfg.Domains)
+	}
+
+	policy := cfg.ChainPolicy([]string{"http", "chromedp", "selenium"})
+	want := []string{"http", "selenium", "chromedp"}
+	for i := range want {
+		if policy.Default[i] != want[i] {
+			t.Fatalf("default chain = %v, want %v", policy.Default, want)
+		}
+	}
+}
+
+func TestLoadConfigValidation(t *testing.T) {
+	t.Setenv("SCRAPER_CONFIG", "")
+	t.Setenv("SCRAPER_ENGINE", "firefox")
+	if _, err := LoadConfig(nil); err == nil {
+		t.Error("expected error for unknown engine")
+	}
+
+	t.Setenv("SCRAPER_ENGINE", "")
+	t.Setenv("TIMEOUT_TWEET", "soon")
+	if _, err := LoadConfig(nil); err == nil {
+		t.Error("expected error for bad duration")
+	}
+}
+
+func TestDefaultConfigDomainRules(t *testing.T) {
+	r := NewRegistry()
+	for _, name := range []string{"http", "chromedp", "selenium"} {
+		r.Register(fakeScraper{name: name})
+	}
+	cfg := DefaultConfig()
+	c, err := NewChain(r, cfg.ChainPolicy(r.Names()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	// 설정 파일이 없어도 x.com, notion.site 는 바로 브라우저로 간다
+	for _, u := range []string{"https://x.com/a/status/1", "https://team.notion.site/page"} {
+		if got := c.Engines(u); len(got) != 2 || got[0] != "chromedp" {
+			t.Errorf("engines for %s = %v", u, got)
+		}
+	}
+	if got := c.Engines("https://kre.pe/abc"); got[0] != "http" {
+		t.Errorf("engines for other hosts = %v", got)
+	}
+}
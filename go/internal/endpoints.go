@@ -0,0 +1,127 @@
+package internal
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// ErrNoHealthyEndpoint : 살아 있는 원격 브라우저가 없을 때
+var ErrNoHealthyEndpoint = errors.New("no healthy browser endpoint")
+
+// HealthCheckFunc : 원격 브라우저 주소 하나가 살아 있는지 확인한다.
+type HealthCheckFunc func(ctx context.Context, endpoint string) error
+
+// Endpoints : 원격 브라우저 주소 목록. 라운드로빈으로 고르고 헬스체크에 실패한 주소는 건너뛴다.
+type Endpoints struct {
+	urls  []string
+	check HealthCheckFunc
+	next  atomic.Uint64
+
+	mu   sync.RWMutex
+	down map[string]bool
+}
+
+func NewEndpoints(urls []string, check HealthCheckFunc) *Endpoints {
+	return &Endpoints{urls: urls, check: check, down: map[string]bool{}}
+}
+
+func (e *Endpoints) Len() int { return len(e.urls) }
+
+// Next : 다음 순서의 살아 있는 주소
+func (e *Endpoints) Next() (string, error) {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+	for range e.urls {
+		u := e.urls[(e.next.Add(1)-1)%uint64(len(e.urls))]
+		if !e.down[u] {
+			return u, nil
+		}
+	}
+	return "", ErrNoHealthyEndpoint
+}
+
+// MarkDown : 연결에 실패한 주소를 다음 헬스체크 전까지 제외한다.
+func (e *Endpoints) MarkDown(endpoint string) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if !e.down[endpoint] {
+		slog.Warn("🔻 Browser endpoint down", "endpoint", endpoint)
+	}
+	e.down[endpoint] = true
+}
+
+// CheckAll : 모든 주소를 한 번씩 검사해서 상태를 갱신한다.
+func (e *Endpoints) CheckAll(ctx context.Context) {
+	for _, u := range e.urls {
+		err := e.check(ctx, u)
+		e.mu.Lock()
+		wasDown := e.down[u]
+		e.down[u] = err != nil
+		e.mu.Unlock()
+		if err != nil && !wasDown {
+			slog.Warn("🔻 Browser endpoint down", "endpoint", u, "err", err)
+		} else if err == nil && wasDown {
+			slog.Info("🔺 Browser endpoint up", "endpoint", u)
+		}
+	}
+}
+
+// Run : ctx가 끝날 때까지 interval마다 헬스체크를 돈다.
+func (e *Endpoints) Run(ctx context.Context, interval time.Duration) {
+	e.CheckAll(ctx)
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			e.CheckAll(ctx)
+		}
+	}
+}
+
+// CheckDevTools : 원격 크롬의 /json/version 이 응답하는지 확인한다.
+// endpoint는 ws://host:9222/devtools/browser/... 또는 http://host:9222 형태.
+func CheckDevTools(ctx context.Context, endpoint string) error {
+	u, err := url.Parse(endpoint)
+	if err != nil {
+		return err
+	}
+	scheme := "http"
+	if u.Scheme == "wss" || u.Scheme == "https" {
+		scheme = "https"
+	}
+	return httpHealthCheck(ctx, fmt.Sprintf("%s://%s/json/version", scheme, u.Host))
+}
+
+// CheckSeleniumHub : Selenium Grid/ChromeDriver의 /status 가 응답하는지 확인한다.
+func CheckSeleniumHub(ctx context.Context, hubURL string) error {
+	return httpHealthCheck(ctx, strings.TrimSuffix(hubURL, "/")+"/status")
+}
+
+func httpHealthCheck(ctx context.Context, target string) error {
+	ctx, cancel := context.WithTimeout(ctx, 3*time.Second)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, target, nil)
+	if err != nil {
+		return err
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("%s: status %d", target, resp.StatusCode)
+	}
+	return nil
+}
@@ -0,0 +1,45 @@
+package internal
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestEndpointsRoundRobin(t *testing.T) {
+	down := map[string]bool{"b": true}
+	e := NewEndpoints([]string{"a", "b", "c"}, func(ctx context.Context, u string) error {
+		if down[u] {
+			return errors.New("down")
+		}
+		return nil
+	})
+	e.CheckAll(context.Background())
+
+	var got []string
+	for i := 0; i < 4; i++ {
+		u, err := e.Next()
+		if err != nil {
+			t.Fatal(err)
+		}
+		got = append(got, u)
+	}
+	want := []string{"a", "c", "a", "c"}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("Next() sequence = %v, want %v", got, want)
+		}
+	}
+
+	e.MarkDown("a")
+	e.MarkDown("c")
+	if _, err := e.Next(); !errors.Is(err, ErrNoHealthyEndpoint) {
+		t.Errorf("expected ErrNoHealthyEndpoint, got %v", err)
+	}
+
+	delete(down, "b")
+	e.CheckAll(context.Background())
+	if u, _ := e.Next(); u == "" {
+		t.Error("expected endpoints to recover after health check")
+	}
+}
@@ -0,0 +1,55 @@
+package internal
+
+import (
+	"context"
+	"sync"
+)
+
+// Scraper : 스크래핑 엔진 공통 인터페이스 (http, chromedp, selenium 등)
+type Scraper interface {
+	Name() string
+	ScrapeTweet(ctx context.Context, url string, opts TweetOptions) (*TweetData, error)
+	ScrapeMeta(ctx context.Context, url string, opts MetaOptions) (*MetaData, error)
+}
+
+// Prober : 브라우저를 실제로 쓸 수 있는지 확인할 수 있는 엔진 (/readyz)
+type Prober interface {
+	Probe(ctx context.Context) error
+}
+
+// probePage : 네트워크 없이 열 수 있는 빈 페이지
+const probePage = "data:text/html,<html><head><title>ok</title></head><body></body></html>"
+
+// Registry : 이름으로 엔진을 등록해 두는 곳. 어떤 엔진을 어떤 순서로 쓸지는 Chain이 정한다.
+type Registry struct {
+	mu      sync.RWMutex
+	engines map[string]Scraper
+	order   []string
+}
+
+func NewRegistry() *Registry {
+	return &Registry{engines: map[string]Scraper{}}
+}
+
+func (r *Registry) Register(s Scraper) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, ok := r.engines[s.Name()]; !ok {
+		r.order = append(r.order, s.Name())
+	}
+	r.engines[s.Name()] = s
+}
+
+func (r *Registry) Get(name string) (Scraper, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	s, ok := r.engines[name]
+	return s, ok
+}
+
+// Names : 등록 순서대로 엔진 이름 목록
+func (r *Registry) Names() []string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return append([]string(nil), r.order...)
+}
@@ -0,0 +1,116 @@
+package internal
+
+import (
+	"context"
+	"time"
+
+	"github.com/chromedp/chromedp"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// ChromedpEngine : chromedp 기반 Scraper 구현. 공유 브라우저의 탭 풀에서 탭을 빌려 쓴다.
+type ChromedpEngine struct {
+	pool     *ChromedpPool
+	timeouts TimeoutConfig
+}
+
+func NewChromedpEngine(pool *ChromedpPool, cfg *Config) *ChromedpEngine {
+	return &ChromedpEngine{pool: pool, timeouts: cfg.Timeouts}
+}
+
+func (e *ChromedpEngine) Name() string { return "chromedp" }
+
+func (e *ChromedpEngine) ScrapeTweet(ctx context.Context, url string, opts TweetOptions) (*TweetData, error) {
+	tab, err := e.lease(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer e.pool.Release(tab)
+	defer meterBrowser(ctx)()
+
+	tabCtx, cancel := bindContext(ctx, tab.Context(), e.timeouts.Tweet)
+	defer cancel()
+	data, err := ScrapeTweetChromedp(tabCtx, url, opts)
+	if err != nil && ctx.Err() != nil {
+		return nil, ctx.Err()
+	}
+	return data, err
+}
+
+func (e *ChromedpEngine) ScrapeMeta(ctx context.Context, url string, opts MetaOptions) (*MetaData, error) {
+	tab, err := e.lease(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer e.pool.Release(tab)
+	defer meterBrowser(ctx)()
+
+	tabCtx, cancel := bindContext(ctx, tab.Context(), e.timeouts.Meta)
+	defer cancel()
+	data, err := ScrapeMetaChromedp(tabCtx, url, contentWait(tabCtx, e.timeouts), opts)
+	if err != nil && ctx.Err() != nil {
+		return nil, ctx.Err()
+	}
+	return data, err
+}
+
+// contentWait : 본문 렌더링 대기 시간. 남은 시간의 절반을 넘지 않게 해서 메타 태그를 읽을 시간을 남긴다.
+func contentWait(ctx context.Context, timeouts TimeoutConfig) time.Duration {
+	wait := timeouts.PageLoad
+	if deadline, ok := ctx.Deadline(); ok {
+		wait = min(wait, time.Until(deadline)/2)
+	}
+	return max(wait, 0)
+}
+
+// lease : 탭을 빌리는 데 걸린 시간을 지표와 span으로 남긴다.
+func (e *ChromedpEngine) lease(ctx context.Context) (*Tab, error) {
+	ctx, span := StartSpan(ctx, "chromedp.lease")
+	start := time.Now()
+	tab, err := e.pool.Lease(ctx)
+	poolWait.WithLabelValues(e.Name()).Observe(time.Since(start).Seconds())
+	EndSpan(span, err)
+	return tab, err
+}
+
+// Probe : 탭을 빌려서 빈 페이지를 열어 본다. 탭이 모두 일하는 중이면 기다리지 않고
+// 브라우저 연결이 응답하는지만 본다. 바쁜 인스턴스를 준비 안 됨으로 빼지 않기 위해서다.
+func (e *ChromedpEngine) Probe(ctx context.Context) error {
+	tab, err := e.pool.TryLease()
+	if err != nil {
+		return err
+	}
+	if tab == nil {
+		return e.pool.Alive(ctx)
+	}
+	defer e.pool.Release(tab)
+
+	tabCtx, cancel := bindContext(ctx, tab.Context(), e.timeouts.Meta)
+	defer cancel()
+	var title string
+	if err := chromedp.Run(tabCtx, chromedp.Navigate(probePage), chromedp.Title(&title)); err != nil {
+		return err
+	}
+	if title != "ok" {
+		return NewError(CodeUnavailable, "unexpected probe page title %q", title)
+	}
+	return nil
+}
+
+// bindContext : 탭 컨텍스트에 엔진 제한 시간을 걸고, 요청 컨텍스트가 먼저 끝나면 같이 끝나게 한다.
+// 요청에 더 이른 마감 시간이 있으면 그쪽을 쓰고, 요청의 span과 요청 ID도 탭 컨텍스트로 옮긴다.
+func bindContext(req, base context.Context, timeout time.Duration) (context.Context, context.CancelFunc) {
+	if deadline, ok := req.Deadline(); ok && time.Until(deadline) < timeout {
+		timeout = time.Until(deadline)
+	}
+	base = trace.ContextWithSpan(base, trace.SpanFromContext(req))
+	if id := RequestID(req); id != "" {
+		base = WithRequestID(base, id)
+	}
+	ctx, cancel := context.WithTimeout(base, timeout)
+	stop := context.AfterFunc(req, cancel)
+	return ctx, func() {
+		stop()
+		cancel()
+	}
+}
@@ -0,0 +1,105 @@
+package internal
+
+import (
+	"context"
+	"time"
+
+	"github.com/tebeka/selenium"
+)
+
+// SeleniumEngine : selenium(ChromeDriver) 기반 Scraper 구현. 세션 풀에서 WebDriver를 빌려 쓴다.
+type SeleniumEngine struct {
+	pool     *SeleniumPool
+	pageLoad time.Duration
+}
+
+func NewSeleniumEngine(pool *SeleniumPool, cfg *Config) *SeleniumEngine {
+	return &SeleniumEngine{pool: pool, pageLoad: cfg.Timeouts.PageLoad}
+}
+
+func (e *SeleniumEngine) Name() string { return "selenium" }
+
+func (e *SeleniumEngine) ScrapeTweet(ctx context.Context, url string, opts TweetOptions) (*TweetData, error) {
+	return withSession(ctx, e.pool, func(wd selenium.WebDriver) (*TweetData, error) {
+		return ScrapeTweet(ctx, wd, url, waitFor(ctx, e.pageLoad), opts)
+	})
+}
+
+func (e *SeleniumEngine) ScrapeMeta(ctx context.Context, url string, opts MetaOptions) (*MetaData, error) {
+	return withSession(ctx, e.pool, func(wd selenium.WebDriver) (*MetaData, error) {
+		return ScrapeMeta(ctx, wd, url, waitFor(ctx, e.pageLoad), opts)
+	})
+}
+
+// Probe : 세션을 빌려서 빈 페이지를 열어 본다. 세션이 모두 일하는 중이면 기다리지 않고
+// ChromeDriver(또는 원격 hub)가 응답하는지만 본다. 바쁜 인스턴스를 준비 안 됨으로 빼지 않기 위해서다.
+func (e *SeleniumEngine) Probe(ctx context.Context) error {
+	s, err := e.pool.TryLease()
+	if err != nil {
+		return err
+	}
+	if s == nil {
+		return e.pool.Alive(ctx)
+	}
+	_, err = runSession(ctx, e.pool, s, func(wd selenium.WebDriver) (*struct{}, error) {
+		if err := wd.Get(probePage); err != nil {
+			return nil, err
+		}
+		title, err := wd.Title()
+		if err != nil {
+			return nil, err
+		}
+		if title != "ok" {
+			return nil, NewError(CodeUnavailable, "unexpected probe page title %q", title)
+		}
+		return nil, nil
+	})
+	return err
+}
+
+// withSession : 세션을 빌려 fn을 실행한다. WebDriver 호출은 컨텍스트를 받지 않아서 별도 고루틴에서 돌리고,
+// 요청이 먼저 끝나면 세션을 종료해 진행 중인 호출을 끊고 자리를 돌려준다.
+func withSession[T any](ctx context.Context, pool *SeleniumPool, fn func(selenium.WebDriver) (*T, error)) (*T, error) {
+	leaseCtx, span := StartSpan(ctx, "selenium.lease")
+	start := time.Now()
+	s, err := pool.Lease(leaseCtx)
+	poolWait.WithLabelValues("selenium").Observe(time.Since(start).Seconds())
+	EndSpan(span, err)
+	if err != nil {
+		return nil, err
+	}
+	return runSession(ctx, pool, s, fn)
+}
+
+// runSession : 빌린 세션으로 fn을 실행하고 세션을 돌려준다 (withSession 참고).
+func runSession[T any](ctx context.Context, pool *SeleniumPool, s *Session, fn func(selenium.WebDriver) (*T, error)) (*T, error) {
+	type result struct {
+		data *T
+		err  error
+	}
+	stopMeter := meterBrowser(ctx)
+	done := make(chan result, 1)
+	go func() {
+		data, err := fn(s.WD)
+		done <- result{data, err}
+	}()
+
+	select {
+	case r := <-done:
+		stopMeter()
+		pool.Release(s, r.err)
+		return r.data, r.err
+	case <-ctx.Done():
+		stopMeter()
+		pool.Discard(s)
+		return nil, ctx.Err()
+	}
+}
+
+// waitFor : 요청 마감 시간이 더 이르면 대기 시간을 그만큼 줄인다.
+func waitFor(ctx context.Context, wait time.Duration) time.Duration {
+	if deadline, ok := ctx.Deadline(); ok && time.Until(deadline) < wait {
+		return max(time.Until(deadline), 0)
+	}
+	return wait
+}
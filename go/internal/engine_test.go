@@ -0,0 +1,161 @@
+package internal
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+type fakeScraper struct {
+	name  string
+	meta  *MetaData
+	tweet *TweetData
+	err   error
+}
+
+func (f fakeScraper) Name() string { return f.name }
+func (f fakeScraper) ScrapeTweet(ctx context.Context, url string, opts TweetOptions) (*TweetData, error) {
+	if f.tweet == nil && f.err == nil {
+		return nil, ErrUnsupported
+	}
+	return f.tweet, f.err
+}
+func (f fakeScraper) ScrapeMeta(ctx context.Context, url string, opts MetaOptions) (*MetaData, error) {
+	return f.meta, f.err
+}
+
+func TestChainFallsBackOnIncompleteResult(t *testing.T) {
+	r := NewRegistry()
+	r.Register(fakeScraper{name: "http", meta: &MetaData{Description: "partial"}})
+	r.Register(fakeScraper{name: "chromedp", meta: &MetaData{Title: "full"}, tweet: &TweetData{Text: "hi"}})
+	r.Register(fakeScraper{name: "selenium", err: errors.New("boom")})
+
+	c, err := NewChain(r, ChainPolicy{
+		Default: []string{"http", "chromedp", "selenium"},
+		Rules:   []ChainRule{{Pattern: "*.notion.site", Engines: []string{"selenium", "http"}}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	meta, err := c.ScrapeMeta(context.Background(), "", "https://example.com", MetaOptions{})
+	if err != nil || meta.Title != "full" || meta.Engine != "chromedp" {
+		t.Errorf("ScrapeMeta default = %+v, %v", meta, err)
+	}
+
+	// 도메인 규칙: selenium 실패 → http의 불완전 결과라도 돌려준다
+	meta, err = c.ScrapeMeta(context.Background(), "", "https://foo.notion.site/page", MetaOptions{})
+	if err != nil || meta.Description != "partial" || meta.Engine != "http" {
+		t.Errorf("ScrapeMeta notion = %+v, %v", meta, err)
+	}
+
+	// http는 트윗을 지원하지 않으니 건너뛴다
+	tweet, err := c.ScrapeTweet(context.Background(), "", "https://x.com/a/status/1", TweetOptions{})
+	if err != nil || tweet.Engine != "chromedp" {
+		t.Errorf("ScrapeTweet = %+v, %v", tweet, err)
+	}
+
+	// 엔진 지정 시 그 엔진만 쓴다
+	if _, err := c.ScrapeMeta(context.Background(), "selenium", "https://example.com", MetaOptions{}); err == nil {
+		t.Error("expected selenium error when engine is forced")
+	}
+	if _, err := c.ScrapeMeta(context.Background(), "nope", "https://example.com", MetaOptions{}); err == nil {
+		t.Error("expected error for unknown engine")
+	}
+
+	if _, err := NewChain(r, ChainPolicy{Default: []string{"nope"}}); err == nil {
+		t.Error("expected error for unknown engine in policy")
+	}
+}
+
+func TestMatchHost(t *testing.T) {
+	cases := []struct {
+		host, pattern string
+		want          bool
+	}{
+		{"x.com", "x.com", true},
+		{"mobile.x.com", "x.com", true},
+		{"xx.com", "x.com", false},
+		{"a.notion.site", "*.notion.site", true},
+		{"notion.site", ".notion.site", true},
+	}
+	for _, c := range cases {
+		if got := MatchHost(c.host, c.pattern); got != c.want {
+			t.Errorf("MatchHost(%q, %q) = %v, want %v", c.host, c.pattern, got, c.want)
+		}
+	}
+}
+
+func TestBindContext(t *testing.T) {
+	req, cancelReq := context.WithCancel(context.Background())
+	ctx, cancel := bindContext(req, context.Background(), time.Minute)
+	defer cancel()
+	cancelReq()
+	select {
+	case <-ctx.Done():
+	case <-time.After(time.Second):
+		t.Fatal("request cancel did not reach the engine context")
+	}
+
+	req, cancelReq = context.WithTimeout(context.Background(), time.Second)
+	defer cancelReq()
+	ctx, cancel = bindContext(req, context.Background(), time.Minute)
+	defer cancel()
+	if deadline, ok := ctx.Deadline(); !ok || time.Until(deadline) > time.Second {
+		t.Errorf("deadline = %v, want request deadline", deadline)
+	}
+}
+
+func TestContentWait(t *testing.T) {
+	timeouts := TimeoutConfig{PageLoad: 10 * time.Second}
+	if got := contentWait(context.Background(), timeouts); got != 10*time.Second {
+		t.Errorf("no deadline: %v", got)
+	}
+	// 본문 대기가 탭 제한 시간을 다 쓰지 않게 남은 시간의 절반까지만 기다린다
+	ctx, cancel := context.WithTimeout(context.Background(), 4*time.Second)
+	defer cancel()
+	if got := contentWait(ctx, timeouts); got > 2*time.Second || got < time.Second {
+		t.Errorf("with deadline: %v", got)
+	}
+}
+
+func TestProbeDoesNotWaitOnBusyPool(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.Chromedp.PoolSize = 1
+	pool := NewChromedpPool(cfg)
+	defer pool.Close()
+	pool.slots <- struct{}{} // 탭 하나가 스크래핑 중
+
+	// 빌릴 탭이 없으면 기다리지 않고 브라우저 연결만 본다. 연결된 브라우저가 없으니 unavailable
+	e := NewChromedpEngine(pool, cfg)
+	start := time.Now()
+	err := e.Probe(context.Background())
+	if Classify(err).Code != CodeUnavailable || time.Since(start) > time.Second {
+		t.Errorf("probe = %v after %v", err, time.Since(start))
+	}
+}
+
+func TestReleaseFreesSlotWhenTabCannotReopen(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.Chromedp.PoolSize = 1
+	cfg.Chromedp.ExecPath = filepath.Join(t.TempDir(), "no-chrome")
+	pool := NewChromedpPool(cfg)
+	defer pool.Close()
+
+	// 빌려 간 탭을 돌려받으면 닫고 새로 여는데, 새 탭을 못 열면 자리를 비운다
+	pool.slots <- struct{}{}
+	ctx, cancel := context.WithCancel(context.Background())
+	pool.Release(&Tab{ctx: ctx, cancel: cancel})
+	if ctx.Err() == nil {
+		t.Error("released tab not closed")
+	}
+	deadline := time.Now().Add(5 * time.Second)
+	for pool.Open() != 0 {
+		if time.Now().After(deadline) {
+			t.Fatalf("slot not freed: open = %d", pool.Open())
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
@@ -0,0 +1,175 @@
+package internal
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"strings"
+
+	"github.com/tebeka/selenium"
+)
+
+// ErrorCode : 클라이언트가 분기할 수 있는 고정 에러 코드
+type ErrorCode string
+
+const (
+	CodeBadRequest       ErrorCode = "bad_request"
+	CodeUnauthorized     ErrorCode = "unauthorized"   // API 키 없음/틀림
+	CodeQuotaExceeded    ErrorCode = "quota_exceeded" // API 키 할당량 초과
+	CodeInvalidURL       ErrorCode = "invalid_url"
+	CodeMethodNotAllowed ErrorCode = "method_not_allowed"
+	CodeNotFound         ErrorCode = "not_found"   // 삭제된 트윗, 404 페이지
+	CodeBlocked          ErrorCode = "blocked"     // 로그인 요구, 접근 차단
+	CodeTimeout          ErrorCode = "timeout"     // 페이지 로딩/요소 대기 시간 초과
+	CodeUnreachable      ErrorCode = "unreachable" // DNS 실패, 연결 거부 등
+	CodeUpstream         ErrorCode = "upstream_error"
+	CodeUnsupported      ErrorCode = "unsupported"
+	CodeUnavailable      ErrorCode = "unavailable"  // 브라우저 풀/작업 큐를 쓸 수 없음
+	CodeRateLimited      ErrorCode = "rate_limited" // 대상 도메인 요청 한도 초과
+	CodeCanceled         ErrorCode = "canceled"
+	CodeInternal         ErrorCode = "internal"
+)
+
+// codeInfo : 코드별 HTTP 상태와 재시도 가능 여부
+var codeInfo = map[ErrorCode]struct {
+	status    int
+	retryable bool
+}{
+	CodeBadRequest:       {http.StatusBadRequest, false},
+	CodeUnauthorized:     {http.StatusUnauthorized, false},
+	CodeQuotaExceeded:    {http.StatusTooManyRequests, false},
+	CodeInvalidURL:       {http.StatusBadRequest, false},
+	CodeMethodNotAllowed: {http.StatusMethodNotAllowed, false},
+	CodeNotFound:         {http.StatusNotFound, false},
+	CodeBlocked:          {http.StatusBadGateway, false},
+	CodeTimeout:          {http.StatusGatewayTimeout, true},
+	CodeUnreachable:      {http.StatusBadGateway, true},
+	CodeUpstream:         {http.StatusBadGateway, true},
+	CodeUnsupported:      {http.StatusUnprocessableEntity, false},
+	CodeUnavailable:      {http.StatusServiceUnavailable, true},
+	CodeRateLimited:      {http.StatusTooManyRequests, true},
+	CodeCanceled:         {499, true}, // 클라이언트가 먼저 끊음 (nginx 관례)
+	CodeInternal:         {http.StatusInternalServerError, false},
+}
+
+// ScrapeError : 분류된 에러. JSON 응답 본문으로 그대로 쓴다.
+type ScrapeError struct {
+	Code      ErrorCode `json:"code"`
+	Message   string    `json:"message"`
+	Retryable bool      `json:"retryable"`
+	Err       error     `json:"-"`
+}
+
+func (e *ScrapeError) Error() string { return e.Message }
+
+func (e *ScrapeError) Unwrap() error { return e.Err }
+
+// Status : 응답에 쓸 HTTP 상태 코드
+func (e *ScrapeError) Status() int {
+	if info, ok := codeInfo[e.Code]; ok {
+		return info.status
+	}
+	return http.StatusInternalServerError
+}
+
+// NewError : 코드와 메시지로 에러를 만든다. 재시도 가능 여부는 코드 기본값을 쓴다.
+func NewError(code ErrorCode, format string, args ...any) *ScrapeError {
+	return &ScrapeError{Code: code, Message: fmt.Sprintf(format, args...), Retryable: codeInfo[code].retryable}
+}
+
+// WrapError : 원래 에러를 감싸서 분류한다. 메시지 뒤에 원래 에러 내용을 붙인다.
+func WrapError(code ErrorCode, err error, format string, args ...any) *ScrapeError {
+	e := NewError(code, format, args...)
+	e.Message += ": " + err.Error()
+	e.Err = err
+	return e
+}
+
+// Classify : 엔진 내부 에러(chromedp, selenium, net/http)를 코드로 분류한다.
+func Classify(err error) *ScrapeError {
+	if err == nil {
+		return nil
+	}
+	var se *ScrapeError
+	if errors.As(err, &se) {
+		return se
+	}
+
+	switch {
+	case errors.Is(err, context.DeadlineExceeded):
+		return WrapError(CodeTimeout, err, "scrape timed out")
+	case errors.Is(err, context.Canceled):
+		return WrapError(CodeCanceled, err, "scrape canceled")
+	case errors.Is(err, ErrPoolClosed), errors.Is(err, ErrQueueFull),
+		errors.Is(err, ErrQueueClosed), errors.Is(err, ErrNoHealthyEndpoint):
+		return WrapError(CodeUnavailable, err, "scraper unavailable")
+	case errors.Is(err, ErrUnsupported):
+		return WrapError(CodeUnsupported, err, "not supported")
+	}
+
+	var dnsErr *net.DNSError
+	if errors.As(err, &dnsErr) {
+		e := WrapError(CodeUnreachable, err, "host not found")
+		e.Retryable = !dnsErr.IsNotFound
+		return e
+	}
+	var netErr net.Error
+	if errors.As(err, &netErr) {
+		if netErr.Timeout() {
+			return WrapError(CodeTimeout, err, "scrape timed out")
+		}
+		return WrapError(CodeUnreachable, err, "connection failed")
+	}
+
+	var wdErr *selenium.Error
+	if errors.As(err, &wdErr) {
+		switch wdErr.Err {
+		case "timeout", "script timeout":
+			return WrapError(CodeTimeout, err, "scrape timed out")
+		case "invalid session id":
+			return WrapError(CodeUnavailable, err, "browser session lost")
+		}
+	}
+
+	// chromedp/selenium이 돌려주는 크롬 네트워크 에러 (page load error net::ERR_...)
+	msg := err.Error()
+	switch {
+	case strings.Contains(msg, "net::ERR_NAME_NOT_RESOLVED"):
+		e := WrapError(CodeUnreachable, err, "host not found")
+		e.Retryable = false
+		return e
+	case strings.Contains(msg, "net::ERR_TIMED_OUT"):
+		return WrapError(CodeTimeout, err, "scrape timed out")
+	case strings.Contains(msg, "net::ERR_"):
+		return WrapError(CodeUnreachable, err, "page load failed")
+	}
+	return WrapError(CodeInternal, err, "scrape failed")
+}
+
+// HTTPStatusError : 대상 페이지가 돌려준 HTTP 상태를 분류한다.
+func HTTPStatusError(status int) *ScrapeError {
+	switch {
+	case status == http.StatusNotFound || status == http.StatusGone:
+		return NewError(CodeNotFound, "page not found (status %d)", status)
+	case status == http.StatusTooManyRequests:
+		e := NewError(CodeBlocked, "rate limited by target (status %d)", status)
+		e.Retryable = true
+		return e
+	case status == http.StatusUnauthorized || status == http.StatusForbidden:
+		return NewError(CodeBlocked, "access denied by target (status %d)", status)
+	case status >= 500:
+		return NewError(CodeUpstream, "target server error (status %d)", status)
+	}
+	return NewError(CodeUpstream, "unexpected status %d", status)
+}
+
+// isTerminal : 다른 엔진으로 다시 시도해도 결과가 같은 에러 (체인을 멈춘다)
+func isTerminal(err error) bool {
+	var se *ScrapeError
+	if !errors.As(err, &se) {
+		return false
+	}
+	return se.Code == CodeNotFound || se.Code == CodeInvalidURL
+}
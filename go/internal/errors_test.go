@@ -0,0 +1,54 @@
+package internal
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"testing"
+
+	"github.com/tebeka/selenium"
+)
+
+func TestClassify(t *testing.T) {
+	cases := []struct {
+		err       error
+		code      ErrorCode
+		status    int
+		retryable bool
+	}{
+		{NewError(CodeNotFound, "tweet not found"), CodeNotFound, http.StatusNotFound, false},
+		{fmt.Errorf("wrapped: %w", context.DeadlineExceeded), CodeTimeout, http.StatusGatewayTimeout, true},
+		{ErrPoolClosed, CodeUnavailable, http.StatusServiceUnavailable, true},
+		{&net.DNSError{Err: "no such host", Name: "nope.invalid", IsNotFound: true}, CodeUnreachable, http.StatusBadGateway, false},
+		{&selenium.Error{Err: "timeout"}, CodeTimeout, http.StatusGatewayTimeout, true},
+		{errors.New("page load error net::ERR_CONNECTION_REFUSED"), CodeUnreachable, http.StatusBadGateway, true},
+		{errors.New("boom"), CodeInternal, http.StatusInternalServerError, false},
+		{HTTPStatusError(http.StatusForbidden), CodeBlocked, http.StatusBadGateway, false},
+	}
+	for _, c := range cases {
+		se := Classify(c.err)
+		if se.Code != c.code || se.Status() != c.status || se.Retryable != c.retryable {
+			t.Errorf("Classify(%v) = %s/%d/%v, want %s/%d/%v",
+				c.err, se.Code, se.Status(), se.Retryable, c.code, c.status, c.retryable)
+		}
+	}
+}
+
+func TestTweetStateError(t *testing.T) {
+	cases := map[string]ErrorCode{
+		"notfound": CodeNotFound,
+		"login":    CodeBlocked,
+		"blocked":  CodeBlocked,
+		"":         CodeTimeout,
+	}
+	if err := tweetStateError("article"); err != nil {
+		t.Errorf("article: %v", err)
+	}
+	for state, code := range cases {
+		if se := Classify(tweetStateError(state)); se.Code != code {
+			t.Errorf("%q: code = %s, want %s", state, se.Code, code)
+		}
+	}
+}
@@ -0,0 +1,153 @@
+package internal
+
+import (
+	"context"
+	"net/url"
+	"strings"
+)
+
+// 결과 종류
+const (
+	TypeTweet = "tweet"
+	TypeMeta  = "meta"
+)
+
+// MetaOptions : 사이트별 메타 추출 방식
+type MetaOptions struct {
+	// ContentSelector : 본문이 자바스크립트로 그려지는 페이지(노션 등)에서 렌더링 완료를 기다릴 요소.
+	// 지정하면 제목은 document.title, 설명은 본문 앞 200자로 채운다. 브라우저 엔진만 지원한다.
+	ContentSelector string
+}
+
+// TweetOptions : 트윗 스크래핑 방식
+type TweetOptions struct {
+	// Thread : 0보다 크면 작성자가 이어서 단 답글(타래)을 최대 Thread개까지 TweetData.Thread에 모은다.
+	Thread int
+}
+
+// Extractor : 호스트 패턴으로 고르는 사이트별 추출기
+type Extractor struct {
+	Name     string
+	Type     string   // TypeTweet | TypeMeta
+	Patterns []string // 호스트 패턴 (x.com, *.notion.site ...)
+	Paths    []string // 경로에 포함돼야 하는 문자열. 비어 있으면 모든 경로
+	Meta     MetaOptions
+}
+
+func (e Extractor) match(u *url.URL) bool {
+	hostOK := false
+	for _, p := range e.Patterns {
+		if MatchHost(u.Hostname(), p) {
+			hostOK = true
+			break
+		}
+	}
+	if !hostOK {
+		return false
+	}
+	if len(e.Paths) == 0 {
+		return true
+	}
+	for _, p := range e.Paths {
+		if strings.Contains(u.Path, p) {
+			return true
+		}
+	}
+	return false
+}
+
+// Extractors : 등록 순서대로 매칭하고, 맞는 게 없으면 일반 페이지(page) 추출기를 쓴다.
+type Extractors struct {
+	list     []Extractor
+	fallback Extractor
+}
+
+func NewExtractors(fallback Extractor) *Extractors {
+	return &Extractors{fallback: fallback}
+}
+
+func (e *Extractors) Register(ex Extractor) {
+	e.list = append(e.list, ex)
+}
+
+// Lookup : rawURL에 맞는 추출기
+func (e *Extractors) Lookup(rawURL string) Extractor {
+	u, err := url.Parse(rawURL)
+	if err != nil || u.Hostname() == "" {
+		return e.fallback
+	}
+	for _, ex := range e.list {
+		if ex.match(u) {
+			return ex
+		}
+	}
+	return e.fallback
+}
+
+// ForType : 요청에서 종류를 지정했을 때 쓸 추출기. 지정한 종류와 맞으면 URL로 찾은 추출기를 그대로 쓴다.
+func (e *Extractors) ForType(rawURL, typ string) (Extractor, error) {
+	ex := e.Lookup(rawURL)
+	switch typ {
+	case "", ex.Type:
+		return ex, nil
+	case TypeTweet:
+		return Extractor{Name: "tweet", Type: TypeTweet}, nil
+	case TypeMeta:
+		return e.fallback, nil
+	}
+	return Extractor{}, NewError(CodeBadRequest, "unknown type: %q", typ)
+}
+
+// DefaultExtractors : 기본 사이트 목록
+func DefaultExtractors() *Extractors {
+	e := NewExtractors(Extractor{Name: "page", Type: TypeMeta})
+	e.Register(Extractor{
+		Name:     "tweet",
+		Type:     TypeTweet,
+		Patterns: []string{"x.com", "twitter.com"},
+		Paths:    []string{"/status/"},
+	})
+	e.Register(Extractor{
+		Name:     "notion",
+		Type:     TypeMeta,
+		Patterns: []string{"notion.site", "notion.so"},
+		Meta:     MetaOptions{ContentSelector: ".notion-page-content"},
+	})
+	e.Register(Extractor{
+		Name:     "krepe",
+		Type:     TypeMeta,
+		Patterns: []string{"kre.pe", "crepe.cm"},
+	})
+	return e
+}
+
+// Envelope : /scrape 공통 응답. Data는 Type에 따라 *TweetData 또는 *MetaData
+type Envelope struct {
+	URL       string `json:"url"`
+	Type      string `json:"type"`
+	Extractor string `json:"extractor"`
+	Engine    string `json:"engine"`
+	Data      any    `json:"data"`
+	CacheInfo
+}
+
+// Extract : 추출기 종류에 맞게 체인을 돌리고 공통 응답으로 감싼다.
+func (c *Chain) Extract(ctx context.Context, engine, pageURL string, ex Extractor) (*Envelope, error) {
+	env := &Envelope{URL: pageURL, Type: ex.Type, Extractor: ex.Name}
+	ctx = withExtractor(ctx, ex, pageURL)
+	switch ex.Type {
+	case TypeTweet:
+		data, err := c.ScrapeTweet(ctx, engine, pageURL, TweetOptions{})
+		if err != nil {
+			return nil, err
+		}
+		env.Engine, env.Data, env.CacheInfo = data.Engine, data, data.CacheInfo
+	default:
+		data, err := c.ScrapeMeta(ctx, engine, pageURL, ex.Meta)
+		if err != nil {
+			return nil, err
+		}
+		env.Engine, env.Data, env.CacheInfo = data.Engine, data, data.CacheInfo
+	}
+	return env, nil
+}
@@ -0,0 +1,33 @@
+package internal
+
+import "testing"
+
+func TestExtractorsLookup(t *testing.T) {
+	e := DefaultExtractors()
+	cases := []struct {
+		url, want string
+	}{
+		{"https://x.com/naeng2_/status/1903488320367403357", "tweet"},
+		{"https://mobile.twitter.com/a/status/1", "tweet"},
+		{"https://x.com/naeng2_", "page"},
+		{"https://grand-mistake-cc4.notion.site/1bd539ffbe2b80849b14c504a7509543", "notion"},
+		{"https://kre.pe/V5LG", "krepe"},
+		{"https://www.naver.com", "page"},
+		{"not a url", "page"},
+	}
+	for _, c := range cases {
+		if got := e.Lookup(c.url).Name; got != c.want {
+			t.Errorf("Lookup(%q) = %s, want %s", c.url, got, c.want)
+		}
+	}
+
+	if ex, _ := e.ForType("https://x.com/naeng2_", TypeTweet); ex.Type != TypeTweet {
+		t.Errorf("ForType tweet = %+v", ex)
+	}
+	if ex, _ := e.ForType("https://x.com/a/status/1", TypeMeta); ex.Name != "page" {
+		t.Errorf("ForType meta = %+v", ex)
+	}
+	if _, err := e.ForType("https://x.com", "video"); err == nil {
+		t.Error("expected error for unknown type")
+	}
+}
@@ -0,0 +1,212 @@
+package internal
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"log/slog"
+	"net/http"
+	"sync"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// JobStatus : 비동기 작업 상태
+type JobStatus string
+
+const (
+	JobQueued  JobStatus = "queued"
+	JobRunning JobStatus = "running"
+	JobDone    JobStatus = "done"
+	JobFailed  JobStatus = "failed"
+)
+
+var (
+	ErrQueueFull   = errors.New("job queue is full")
+	ErrQueueClosed = errors.New("job queue is closed")
+)
+
+// Job : POST /jobs 로 만든 비동기 스크래핑 작업
+type Job struct {
+	ID          string       `json:"id"`
+	Status      JobStatus    `json:"status"`
+	URL         string       `json:"url"`
+	Type        string       `json:"type,omitempty"`
+	Engine      string       `json:"engine,omitempty"`
+	CallbackURL string       `json:"callback_url,omitempty"`
+	APIKey      string       `json:"-"` // 요청한 API 키 이름. 브라우저 사용 시간을 이 키에 더한다
+	RequestID   string       `json:"-"` // 작업을 만든 요청의 ID. 작업 로그에 그대로 붙인다
+	Result      *Envelope    `json:"result,omitempty"`
+	Error       *ScrapeError `json:"error,omitempty"`
+	CreatedAt   time.Time    `json:"created_at"`
+	UpdatedAt   time.Time    `json:"updated_at"`
+}
+
+// JobRunner : 작업 하나를 실제로 스크래핑한다.
+type JobRunner func(ctx context.Context, job Job) (*Envelope, error)
+
+// JobQueue : 요청과 상관없이 백그라운드 워커가 작업을 처리하고, 끝난 작업은 TTL 동안 보관한다.
+// callback_url이 있으면 끝난 작업을 POST로 보낸다.
+type JobQueue struct {
+	cfg    JobsConfig
+	run    JobRunner
+	client *http.Client
+
+	ctx    context.Context // 워커 수명. Close 시간이 넘으면 취소해서 남은 작업을 중단한다
+	cancel context.CancelFunc
+	queue  chan string
+	wg     sync.WaitGroup // 워커와 보내는 중인 콜백
+
+	mu     sync.Mutex
+	jobs   map[string]*Job
+	closed bool
+}
+
+func NewJobQueue(cfg JobsConfig, run JobRunner) *JobQueue {
+	ctx, cancel := context.WithCancel(context.Background())
+	q := &JobQueue{
+		cfg:    cfg,
+		run:    run,
+		client: newCallbackClient(),
+		ctx:    ctx,
+		cancel: cancel,
+		queue:  make(chan string, cfg.QueueSize),
+		jobs:   map[string]*Job{},
+	}
+	for i := 0; i < cfg.Workers; i++ {
+		q.wg.Add(1)
+		go q.worker()
+	}
+	go q.janitor()
+	return q
+}
+
+// Submit : 작업을 큐에 넣고 바로 돌려준다.
+func (q *JobQueue) Submit(job Job) (Job, error) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if q.closed {
+		return Job{}, ErrQueueClosed
+	}
+
+	now := time.Now().UTC()
+	job.ID = newJobID()
+	job.Status = JobQueued
+	job.CreatedAt, job.UpdatedAt = now, now
+
+	select {
+	case q.queue <- job.ID:
+	default:
+		return Job{}, ErrQueueFull
+	}
+	q.jobs[job.ID] = &job
+	return job, nil
+}
+
+// Get : 작업 상태 조회 (복사본)
+func (q *JobQueue) Get(id string) (Job, bool) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	job, ok := q.jobs[id]
+	if !ok {
+		return Job{}, false
+	}
+	return *job, true
+}
+
+// Close : 새 작업을 받지 않고 큐에 남은 작업과 보내는 중인 콜백까지 끝나기를 기다린다.
+// ctx가 먼저 끝나면 실행 중인 작업을 취소한다.
+func (q *JobQueue) Close(ctx context.Context) error {
+	q.mu.Lock()
+	if !q.closed {
+		q.closed = true
+		close(q.queue)
+	}
+	q.mu.Unlock()
+
+	done := make(chan struct{})
+	go func() {
+		q.wg.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+		q.cancel()
+		return nil
+	case <-ctx.Done():
+		q.cancel()
+		<-done
+		return ctx.Err()
+	}
+}
+
+func (q *JobQueue) worker() {
+	defer q.wg.Done()
+	for id := range q.queue {
+		job := q.update(id, func(j *Job) { j.Status = JobRunning })
+
+		ctx, span := StartSpan(WithRequestID(q.ctx, job.RequestID), "job.run",
+			attribute.String("job.id", job.ID), attribute.String("url.full", job.URL))
+		result, err := q.run(ctx, job)
+		EndSpan(span, err)
+		job = q.update(id, func(j *Job) {
+			if err != nil {
+				j.Status, j.Error = JobFailed, Classify(err)
+			} else {
+				j.Status, j.Result = JobDone, result
+			}
+		})
+		slog.InfoContext(ctx, "📮 Job finished", "job_id", job.ID, "status", job.Status, "url", job.URL)
+
+		// 콜백은 따로 보내서 느린 콜백 주소가 워커를 붙잡지 않게 한다
+		if job.CallbackURL != "" {
+			q.wg.Add(1)
+			go func() {
+				defer q.wg.Done()
+				q.callback(job)
+			}()
+		}
+	}
+}
+
+// update : 잠금 상태에서 작업을 고치고 복사본을 돌려준다.
+func (q *JobQueue) update(id string, fn func(*Job)) Job {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	job := q.jobs[id]
+	fn(job)
+	job.UpdatedAt = time.Now().UTC()
+	return *job
+}
+
+// janitor : TTL이 지난 끝난 작업을 지운다.
+func (q *JobQueue) janitor() {
+	ticker := time.NewTicker(time.Minute)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-q.ctx.Done():
+			return
+		case <-ticker.C:
+			q.prune(time.Now().Add(-q.cfg.TTL))
+		}
+	}
+}
+
+func (q *JobQueue) prune(before time.Time) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	for id, job := range q.jobs {
+		if (job.Status == JobDone || job.Status == JobFailed) && job.UpdatedAt.Before(before) {
+			delete(q.jobs, id)
+		}
+	}
+}
+
+func newJobID() string {
+	b := make([]byte, 8)
+	_, _ = rand.Read(b)
+	return hex.EncodeToString(b)
+}
@@ -0,0 +1,136 @@
+package internal
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestJobQueue(t *testing.T) {
+	callbacks := make(chan Job, 1)
+	hook := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var job Job
+		json.NewDecoder(r.Body).Decode(&job)
+		callbacks <- job
+	}))
+	defer hook.Close()
+
+	release := make(chan struct{})
+	q := NewJobQueue(JobsConfig{Workers: 1, QueueSize: 1, TTL: time.Hour}, func(ctx context.Context, job Job) (*Envelope, error) {
+		<-release
+		if job.URL == "https://fail.example" {
+			return nil, errors.New("boom")
+		}
+		return &Envelope{URL: job.URL, Type: TypeMeta, Data: &MetaData{Title: "ok"}}, nil
+	})
+	q.client = hook.Client() // 테스트 서버는 루프백이라 공개 주소 검사를 건너뛴다
+
+	ok, err := q.Submit(Job{URL: "https://ok.example", CallbackURL: hook.URL})
+	if err != nil {
+		t.Fatal(err)
+	}
+	waitStatus(t, q, ok.ID, JobRunning)
+
+	failed, err := q.Submit(Job{URL: "https://fail.example"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := q.Submit(Job{URL: "https://full.example"}); !errors.Is(err, ErrQueueFull) {
+		t.Errorf("expected ErrQueueFull, got %v", err)
+	}
+
+	close(release)
+	if job := waitStatus(t, q, ok.ID, JobDone); job.Result == nil {
+		t.Error("done job has no result")
+	}
+	if job := waitStatus(t, q, failed.ID, JobFailed); job.Error == nil || !strings.Contains(job.Error.Message, "boom") {
+		t.Errorf("failed job error = %+v", job.Error)
+	}
+
+	select {
+	case job := <-callbacks:
+		if job.ID != ok.ID || job.Status != JobDone {
+			t.Errorf("callback job = %+v", job)
+		}
+	case <-time.After(2 * time.Second):
+		t.Error("callback not received")
+	}
+
+	if err := q.Close(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := q.Submit(Job{URL: "https://late.example"}); !errors.Is(err, ErrQueueClosed) {
+		t.Errorf("expected ErrQueueClosed, got %v", err)
+	}
+}
+
+func waitStatus(t *testing.T, q *JobQueue, id string, want JobStatus) Job {
+	t.Helper()
+	deadline := time.Now().Add(2 * time.Second)
+	for time.Now().Before(deadline) {
+		if job, _ := q.Get(id); job.Status == want {
+			return job
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	job, _ := q.Get(id)
+	t.Fatalf("job %s status = %s, want %s", id, job.Status, want)
+	return job
+}
+
+func TestJobCallbackDoesNotBlockWorker(t *testing.T) {
+	unblock := make(chan struct{})
+	hook := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-unblock
+	}))
+	defer hook.Close()
+
+	q := NewJobQueue(JobsConfig{Workers: 1, QueueSize: 2, TTL: time.Hour}, func(ctx context.Context, job Job) (*Envelope, error) {
+		return &Envelope{URL: job.URL, Type: TypeMeta, Data: &MetaData{Title: "ok"}}, nil
+	})
+	q.client = hook.Client()
+	defer q.Close(context.Background())
+	defer close(unblock) // Close가 보내는 중인 콜백을 기다리므로 먼저 풀어 준다
+
+	q.Submit(Job{URL: "https://slow-hook.example", CallbackURL: hook.URL})
+	next, _ := q.Submit(Job{URL: "https://next.example"})
+	waitStatus(t, q, next.ID, JobDone)
+}
+
+func TestCallbackClientRefusesPrivateAddresses(t *testing.T) {
+	hook := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Error("callback reached a loopback address")
+	}))
+	defer hook.Close()
+
+	resp, err := newCallbackClient().Post(hook.URL, "application/json", strings.NewReader("{}"))
+	if err == nil {
+		resp.Body.Close()
+		t.Fatal("expected dial error")
+	}
+
+	for host, want := range map[string]bool{
+		"8.8.8.8":         true,
+		"2606:4700::1111": true,
+		"example.com":     true,
+		"127.0.0.1":       false,
+		"10.1.2.3":        false,
+		"192.168.0.10":    false,
+		"169.254.169.254": false,
+		"100.64.0.1":      false,
+		"::1":             false,
+		"::ffff:10.0.0.1": false,
+		"fe80::1":         false,
+		"localhost":       false,
+		"api.localhost":   false,
+	} {
+		if got := CheckCallbackHost(host) == nil; got != want {
+			t.Errorf("CheckCallbackHost(%q) allowed = %v, want %v", host, got, want)
+		}
+	}
+}
@@ -0,0 +1,77 @@
+package internal
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"io"
+	"log/slog"
+	"strings"
+)
+
+// logFields : log.fields 가 켜져 있을 때만 필드 값 로그를 남긴다.
+var logFields bool
+
+// NewLogger : 설정에 맞는 slog 로거. 컨텍스트에 요청 ID가 있으면 request_id 로 붙인다.
+func NewLogger(cfg LogConfig, w io.Writer) *slog.Logger {
+	var level slog.Level
+	if err := level.UnmarshalText([]byte(cfg.Level)); err != nil {
+		level = slog.LevelInfo
+	}
+	opts := &slog.HandlerOptions{Level: level}
+
+	var h slog.Handler
+	if strings.EqualFold(cfg.Format, "text") {
+		h = slog.NewTextHandler(w, opts)
+	} else {
+		h = slog.NewJSONHandler(w, opts)
+	}
+	logFields = cfg.Fields
+	return slog.New(contextHandler{h})
+}
+
+// contextHandler : 컨텍스트의 요청 ID를 모든 로그에 붙인다.
+type contextHandler struct {
+	slog.Handler
+}
+
+func (h contextHandler) Handle(ctx context.Context, r slog.Record) error {
+	if id := RequestID(ctx); id != "" {
+		r.AddAttrs(slog.String("request_id", id))
+	}
+	return h.Handler.Handle(ctx, r)
+}
+
+func (h contextHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return contextHandler{h.Handler.WithAttrs(attrs)}
+}
+
+func (h contextHandler) WithGroup(name string) slog.Handler {
+	return contextHandler{h.Handler.WithGroup(name)}
+}
+
+type requestIDKey struct{}
+
+// WithRequestID : 요청 ID를 컨텍스트에 싣는다. 엔진 호출까지 그대로 따라간다.
+func WithRequestID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, requestIDKey{}, id)
+}
+
+func RequestID(ctx context.Context) string {
+	id, _ := ctx.Value(requestIDKey{}).(string)
+	return id
+}
+
+// NewRequestID : 16자리 16진수 ID
+func NewRequestID() string {
+	b := make([]byte, 8)
+	_, _ = rand.Read(b)
+	return hex.EncodeToString(b)
+}
+
+// debugField : 스크래핑한 필드 값 하나를 debug 로그로 남긴다 (log.fields).
+func debugField(ctx context.Context, msg, field, value string) {
+	if logFields {
+		slog.DebugContext(ctx, msg, "field", field, "value", value)
+	}
+}
@@ -0,0 +1,45 @@
+package internal
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"strings"
+	"testing"
+)
+
+func TestLoggerRequestID(t *testing.T) {
+	var buf bytes.Buffer
+	logger := NewLogger(LogConfig{Level: "info", Format: "json"}, &buf)
+
+	ctx := WithRequestID(context.Background(), "abc123")
+	logger.InfoContext(ctx, "hello", "url", "https://x.com")
+	logger.DebugContext(ctx, "hidden")
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 1 {
+		t.Fatalf("got %d lines, want 1 (debug filtered): %q", len(lines), buf.String())
+	}
+	var entry map[string]any
+	if err := json.Unmarshal([]byte(lines[0]), &entry); err != nil {
+		t.Fatal(err)
+	}
+	if entry["request_id"] != "abc123" || entry["msg"] != "hello" || entry["url"] != "https://x.com" {
+		t.Errorf("entry = %v", entry)
+	}
+}
+
+func TestDebugFieldGated(t *testing.T) {
+	defer slog.SetDefault(slog.Default())
+
+	for _, fields := range []bool{false, true} {
+		var buf bytes.Buffer
+		slog.SetDefault(NewLogger(LogConfig{Level: "debug", Format: "json", Fields: fields}, &buf))
+		debugField(context.Background(), "🏷 Title", "title", "secret title")
+		if got := strings.Contains(buf.String(), "secret title"); got != fields {
+			t.Errorf("fields=%v: logged=%v (%q)", fields, got, buf.String())
+		}
+	}
+	logFields = false
+}
@@ -1,9 +1,9 @@
 package internal
 
 import (
+	"context"
 	"fmt"
-	"log"
-	"strings"
+	"log/slog"
 	"time"
 
 	"github.com/tebeka/selenium"
@@ -17,40 +17,56 @@ type MetaData struct {
 	Description string `json:"description"`
 	Image       string `json:"img"`
 	URL         string `json:"url"`
+	Engine      string `json:"engine,omitempty"` // 결과를 만든 엔진
+	CacheInfo
 }
 
-// ScrapeMeta : 일반 페이지의 메타데이터 스크래핑
-func ScrapeMeta(wd selenium.WebDriver, pageURL string) (*MetaData, error) {
-	log.Printf("📥 Scraping meta: %s", pageURL)
+// complete : 제목이 있으면 완전한 결과로 본다.
+func (m *MetaData) complete() bool { return m.Title != "" }
+
+// cacheable : 완전한 결과면 캐시한다.
+func (m *MetaData) cacheable() bool { return m.complete() }
+
+// emptyFields : 비어 있는 필드 이름 (지표용)
+func (m *MetaData) emptyFields() []string {
+	return emptyFieldNames(map[string]bool{
+		"title":       m.Title == "",
+		"description": m.Description == "",
+		"img":         m.Image == "",
+	})
+}
+
+// ScrapeMeta : 일반 페이지의 메타데이터 스크래핑. wait는 페이지 로딩/본문 렌더링 대기 시간
+func ScrapeMeta(ctx context.Context, wd selenium.WebDriver, pageURL string, wait time.Duration, opts MetaOptions) (*MetaData, error) {
+	slog.InfoContext(ctx, "📥 Scraping meta", "url", pageURL)
 	startTime := time.Now()
 	meta := &MetaData{URL: pageURL}
 
 	// 페이지 로딩
-	if err := wd.Get(pageURL); err != nil {
+	if err := navigate(ctx, wd, pageURL, wait); err != nil {
 		return nil, err
 	}
-	if err := WaitForPageLoad(wd, 10); err != nil {
-		return nil, fmt.Errorf("failed to wait for page load: %v", err)
-	}
-	log.Printf("✅ Page loaded in %v", time.Since(startTime))
+	slog.DebugContext(ctx, "✅ Page loaded", "elapsed", time.Since(startTime))
 
 	// === Title ===
-	if strings.Contains(pageURL, ".notion.") {
-		log.Printf("🔍 Handling Notion title...")
+	if opts.ContentSelector != "" {
+		// 본문이 자바스크립트로 그려지는 페이지(노션 등)는 렌더링 후 document.title을 쓴다
+		slog.DebugContext(ctx, "🔍 Waiting for content", "selector", opts.ContentSelector)
+		contentScript := fmt.Sprintf(`return document.querySelector(%q)?.innerText || "";`, opts.ContentSelector)
 		wd.WaitWithTimeout(func(wd selenium.WebDriver) (bool, error) {
-			script := `return document.querySelector(".notion-page-content")?.innerText;`
-			text, err := wd.ExecuteScript(script, nil)
-			return text != nil && text.(string) != "", err
-		}, 10*time.Second)
+			text, err := wd.ExecuteScript(contentScript, nil)
+			s, _ := text.(string)
+			return s != "", err
+		}, wait)
 
 		titleJS, err := wd.ExecuteScript("return document.title;", nil)
 		if err == nil {
-			meta.Title = titleJS.(string)
+			meta.Title, _ = titleJS.(string)
 		}
 	} else {
-		elem, err := wd.FindElement(selenium.ByXPATH, `//meta[@property="og:title"]`)
+		elem, err := findElement(ctx, wd, selenium.ByXPATH, `//meta[@property="og:title"]`)
 		if err != nil {
-			elem, err = wd.FindElement(selenium.ByXPATH, `//head/title`)
+			elem, err = findElement(ctx, wd, selenium.ByXPATH, `//head/title`)
 			if err == nil {
 				meta.Title, _ = elem.Text()
 			}
@@ -58,39 +74,31 @@ func ScrapeMeta(wd selenium.WebDriver, pageURL string) (*MetaData, error) {
 			meta.Title, _ = elem.GetAttribute("content")
 		}
 	}
-	log.Printf("🏷 Title: %s", meta.Title)
+	debugField(ctx, "🏷 Title", "title", meta.Title)
 
 	// === Image ===
-	imgElem, err := wd.FindElement(selenium.ByXPATH, `//meta[@property="og:image"]`)
+	imgElem, err := findElement(ctx, wd, selenium.ByXPATH, `//meta[@property="og:image"]`)
 	if err != nil {
-		imgElem, err = wd.FindElement(selenium.ByXPATH, `//meta[@name="image"]`)
+		imgElem, err = findElement(ctx, wd, selenium.ByXPATH, `//meta[@name="image"]`)
 	}
 	if imgElem != nil {
 		meta.Image, _ = imgElem.GetAttribute("content")
 	}
-	log.Printf("🖼 Image: %s", meta.Image)
+	debugField(ctx, "🖼 Image", "img", meta.Image)
 
 	// === Description ===
-	if strings.Contains(pageURL, ".notion.") {
-		log.Printf("🔍 Handling Notion description...")
-		wd.WaitWithTimeout(func(wd selenium.WebDriver) (bool, error) {
-			script := `return document.querySelector(".notion-page-content")?.innerText;`
-			text, err := wd.ExecuteScript(script, nil)
-			return text != nil && text.(string) != "", err
-		}, 10*time.Second)
-
-		descJS, err := wd.ExecuteScript(`return document.querySelector(".notion-page-content")?.innerText.slice(0, 200);`, nil)
-		if err == nil {
-			clean := lib.CleanText(descJS.(string))
-			if len(clean) > 200 {
-				clean = clean[:200] + "..."
-			}
-			meta.Description = clean
+	// 본문이 끝내 안 그려졌으면 메타 태그로 채운다 (chromedp와 같음)
+	if opts.ContentSelector != "" {
+		script := fmt.Sprintf(`return document.querySelector(%q)?.innerText || "";`, opts.ContentSelector)
+		if descJS, err := wd.ExecuteScript(script, nil); err == nil {
+			text, _ := descJS.(string)
+			meta.Description = summarizeContent(text)
 		}
-	} else {
-		descElem, err := wd.FindElement(selenium.ByXPATH, `//meta[@property="og:description"]`)
+	}
+	if meta.Description == "" {
+		descElem, err := findElement(ctx, wd, selenium.ByXPATH, `//meta[@property="og:description"]`)
 		if err != nil {
-			descElem, err = wd.FindElement(selenium.ByCSSSelector, `meta[name="description"]`)
+			descElem, err = findElement(ctx, wd, selenium.ByCSSSelector, `meta[name="description"]`)
 			if err == nil {
 				meta.Description, _ = descElem.GetAttribute("content")
 			}
@@ -98,8 +106,17 @@ func ScrapeMeta(wd selenium.WebDriver, pageURL string) (*MetaData, error) {
 			meta.Description, _ = descElem.GetAttribute("content")
 		}
 	}
-	log.Printf("📝 Description: %s", meta.Description)
+	debugField(ctx, "📝 Description", "description", meta.Description)
 
-	log.Printf("✅ Done scraping meta: %s (%v)", pageURL, time.Since(startTime))
+	slog.InfoContext(ctx, "✅ Done scraping meta", "url", pageURL, "elapsed", time.Since(startTime))
 	return meta, nil
 }
+
+// summarizeContent : 본문 텍스트를 정리해서 설명으로 쓸 앞부분 200자만 남긴다.
+func summarizeContent(text string) string {
+	clean := lib.CleanText(text)
+	if r := []rune(clean); len(r) > 200 {
+		clean = string(r[:200]) + "..."
+	}
+	return clean
+}
@@ -2,32 +2,54 @@ package internal
 
 import (
 	"context"
+	"fmt"
+	"log/slog"
 	"strings"
 	"time"
 
 	"github.com/chromedp/chromedp"
 )
 
-// ScrapeMetaChromedp : chromedp 버전
-func ScrapeMetaChromedp(parent context.Context, pageURL string) (*MetaData, error) {
-	ctx, cancel := context.WithTimeout(parent, 15*time.Second)
-	defer cancel()
+// ScrapeMetaChromedp : chromedp 버전. ctx에 제한 시간을 걸어서 넘긴다.
+// wait는 본문(ContentSelector) 렌더링 대기 시간. 본문이 끝내 안 그려지면 메타 태그로 채운다 (selenium과 같음).
+func ScrapeMetaChromedp(ctx context.Context, pageURL string, wait time.Duration, opts MetaOptions) (*MetaData, error) {
+	var title, desc, image, content string
 
-	var title, desc, image string
-
-	tasks := chromedp.Tasks{
+	err := chromedp.Run(ctx, tracedAction("chromedp.navigate",
 		chromedp.Navigate(pageURL),
 		chromedp.WaitReady("body", chromedp.ByQuery),
-
-		chromedp.Title(&title),
-		chromedp.AttributeValue(`meta[name="description"]`, "content", &desc, nil),
-		chromedp.AttributeValue(`meta[property="og:image"]`, "content", &image, nil),
+	))
+	if err != nil {
+		return nil, err
+	}
+	if opts.ContentSelector != "" {
+		// 본문이 자바스크립트로 그려질 때까지 기다린 뒤 본문 앞부분을 설명으로 쓴다
+		err := chromedp.Run(ctx, tracedAction("chromedp.wait_content", chromedp.Poll(
+			fmt.Sprintf(`document.querySelector(%q)?.innerText || ""`, opts.ContentSelector),
+			&content,
+			chromedp.WithPollingTimeout(wait),
+		)))
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		if err != nil {
+			slog.DebugContext(ctx, "⌛ Content not rendered, using meta tags", "selector", opts.ContentSelector, "err", err)
+		}
 	}
 
+	tasks := chromedp.Tasks{
+		tracedAction("extract.title", chromedp.Title(&title)),
+		tracedAction("extract.description", chromedp.AttributeValue(`meta[name="description"]`, "content", &desc, nil)),
+		tracedAction("extract.img", chromedp.AttributeValue(`meta[property="og:image"]`, "content", &image, nil)),
+	}
 	if err := chromedp.Run(ctx, tasks); err != nil {
 		return nil, err
 	}
 
+	if summary := summarizeContent(content); summary != "" {
+		desc = summary
+	}
+
 	return &MetaData{
 		Title:       strings.TrimSpace(title),
 		Description: strings.TrimSpace(desc),
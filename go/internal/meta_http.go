@@ -0,0 +1,212 @@
+package internal
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"mime"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/charset"
+)
+
+const (
+	maxMetaBodyBytes = 2 << 20 // 2MB. 메타 태그는 <head>에 있으니 이 이상 읽을 필요 없음
+	maxMetaRedirects = 5
+)
+
+// ErrUnsupported : 엔진이 지원하지 않는 스크래핑 종류
+var ErrUnsupported = errors.New("not supported by this engine")
+
+// HTTPEngine : 브라우저 없이 net/http로 정적 HTML의 메타 태그만 읽는 엔진.
+// 트윗은 자바스크립트 렌더링이 필요해서 지원하지 않는다.
+type HTTPEngine struct {
+	client         *http.Client
+	userAgent      string
+	acceptLanguage string // browser.lang 로 만든 Accept-Language
+}
+
+func NewHTTPEngine(cfg *Config) *HTTPEngine {
+	return &HTTPEngine{userAgent: cfg.Browser.UserAgent, acceptLanguage: acceptLanguage(cfg.Browser.Lang), client: &http.Client{
+		Timeout: cfg.Timeouts.HTTP,
+		CheckRedirect: func(req *http.Request, via []*http.Request) error {
+			if len(via) >= maxMetaRedirects {
+				return fmt.Errorf("stopped after %d redirects", maxMetaRedirects)
+			}
+			return nil
+		},
+	}}
+}
+
+func (e *HTTPEngine) Name() string { return "http" }
+
+func (e *HTTPEngine) ScrapeTweet(ctx context.Context, url string, opts TweetOptions) (*TweetData, error) {
+	return nil, fmt.Errorf("http engine: tweet %w", ErrUnsupported)
+}
+
+// ScrapeMeta : 렌더링이 필요한 페이지(opts.ContentSelector)는 지원하지 않는다.
+func (e *HTTPEngine) ScrapeMeta(ctx context.Context, pageURL string, opts MetaOptions) (*MetaData, error) {
+	if opts.ContentSelector != "" {
+		return nil, fmt.Errorf("http engine: rendered content %w", ErrUnsupported)
+	}
+	return ScrapeMetaHTTP(ctx, e.client, e.userAgent, e.acceptLanguage, pageURL)
+}
+
+// acceptLanguage : browser.lang ("ko-KR,ko") 을 브라우저처럼 q 값을 붙인 Accept-Language 로 만든다.
+// 예) "ko-KR,ko,en" → "ko-KR,ko;q=0.9,en;q=0.8"
+func acceptLanguage(lang string) string {
+	var parts []string
+	for _, l := range strings.Split(lang, ",") {
+		if l = strings.TrimSpace(l); l == "" {
+			continue
+		}
+		if len(parts) > 0 {
+			l += fmt.Sprintf(";q=%.1f", max(1-0.1*float64(len(parts)), 0.1))
+		}
+		parts = append(parts, l)
+	}
+	return strings.Join(parts, ",")
+}
+
+// ScrapeMetaHTTP : HTML을 받아서 og/meta/title 태그로 MetaData를 채운다. acceptLanguage가 비어 있으면 헤더를 보내지 않는다.
+func ScrapeMetaHTTP(ctx context.Context, client *http.Client, userAgent, acceptLanguage, pageURL string) (*MetaData, error) {
+	startTime := time.Now()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, pageURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("User-Agent", userAgent)
+	req.Header.Set("Accept", "text/html,application/xhtml+xml")
+	if acceptLanguage != "" {
+		req.Header.Set("Accept-Language", acceptLanguage)
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, HTTPStatusError(resp.StatusCode)
+	}
+	contentType := resp.Header.Get("Content-Type")
+	if mediaType, _, _ := mime.ParseMediaType(contentType); mediaType != "" &&
+		mediaType != "text/html" && mediaType != "application/xhtml+xml" {
+		return nil, NewError(CodeUnsupported, "not an html page: %s", mediaType)
+	}
+
+	// Content-Type 헤더, BOM, <meta charset> 순으로 인코딩을 판별해서 UTF-8로 변환
+	body, err := charset.NewReader(io.LimitReader(resp.Body, maxMetaBodyBytes), contentType)
+	if err != nil {
+		return nil, fmt.Errorf("failed to detect charset: %w", err)
+	}
+
+	meta := parseMetaTags(body, resp.Request.URL)
+	meta.URL = pageURL
+	slog.InfoContext(ctx, "✅ Done fetching meta", "url", pageURL, "elapsed", time.Since(startTime))
+	return meta, nil
+}
+
+// parseMetaTags : <head>를 훑어서 제목/설명/이미지를 찾는다. og 태그를 우선한다.
+func parseMetaTags(r io.Reader, base *url.URL) *MetaData {
+	var (
+		title, ogTitle      string
+		desc, ogDesc        string
+		image, ogImage      string
+		inTitle, titleFound bool
+	)
+
+	z := html.NewTokenizer(r)
+loop:
+	for {
+		tt := z.Next()
+		switch tt {
+		case html.ErrorToken:
+			break loop
+		case html.StartTagToken, html.SelfClosingTagToken:
+			tok := z.Token()
+			switch tok.Data {
+			case "title":
+				inTitle = !titleFound
+			case "meta":
+				key, content := metaKeyContent(tok)
+				switch key {
+				case "og:title":
+					ogTitle = content
+				case "og:description":
+					ogDesc = content
+				case "og:image":
+					ogImage = content
+				case "description":
+					desc = content
+				case "image":
+					image = content
+				}
+			case "body":
+				break loop
+			}
+		case html.TextToken:
+			if inTitle {
+				title += string(z.Text())
+			}
+		case html.EndTagToken:
+			tok := z.Token()
+			switch tok.Data {
+			case "title":
+				inTitle, titleFound = false, true
+			case "head":
+				break loop
+			}
+		}
+	}
+
+	return &MetaData{
+		Title:       strings.TrimSpace(firstNonEmpty(ogTitle, title)),
+		Description: strings.TrimSpace(firstNonEmpty(ogDesc, desc)),
+		Image:       resolveURL(base, strings.TrimSpace(firstNonEmpty(ogImage, image))),
+	}
+}
+
+// metaKeyContent : <meta property|name="..." content="..."> 에서 키와 값을 꺼낸다.
+func metaKeyContent(tok html.Token) (key, content string) {
+	for _, a := range tok.Attr {
+		switch strings.ToLower(a.Key) {
+		case "property", "name":
+			if key == "" {
+				key = strings.ToLower(strings.TrimSpace(a.Val))
+			}
+		case "content":
+			content = a.Val
+		}
+	}
+	return key, content
+}
+
+func firstNonEmpty(vals ...string) string {
+	for _, v := range vals {
+		if strings.TrimSpace(v) != "" {
+			return v
+		}
+	}
+	return ""
+}
+
+// resolveURL : 상대 경로 이미지 주소를 최종 페이지 주소 기준으로 절대 경로로 바꾼다.
+func resolveURL(base *url.URL, ref string) string {
+	if ref == "" || base == nil {
+		return ref
+	}
+	u, err := url.Parse(ref)
+	if err != nil {
+		return ref
+	}
+	return base.ResolveReference(u).String()
+}
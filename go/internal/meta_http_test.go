@@ -0,0 +1,85 @@
+package internal
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"golang.org/x/text/encoding/korean"
+)
+
+func TestScrapeMetaHTTP(t *testing.T) {
+	page := `<html><head>
+<meta charset="euc-kr">
+<title>네이버 제목</title>
+<meta name="description" content="기본 설명">
+<meta property="og:description" content="오픈그래프 설명">
+<meta property="og:image" content="/img/logo.png">
+</head><body><meta property="og:title" content="본문 안의 태그는 무시"></body></html>`
+	encoded, err := korean.EUCKR.NewEncoder().String(page)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/old", func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, "/page/", http.StatusFound)
+	})
+	mux.HandleFunc("/page/", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html")
+		w.Write([]byte(encoded))
+	})
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+
+	meta, err := NewHTTPEngine(DefaultConfig()).ScrapeMeta(context.Background(), srv.URL+"/old", MetaOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if meta.Title != "네이버 제목" {
+		t.Errorf("Title = %q", meta.Title)
+	}
+	if meta.Description != "오픈그래프 설명" {
+		t.Errorf("Description = %q", meta.Description)
+	}
+	if meta.Image != srv.URL+"/img/logo.png" {
+		t.Errorf("Image = %q", meta.Image)
+	}
+	if meta.URL != srv.URL+"/old" {
+		t.Errorf("URL = %q", meta.URL)
+	}
+}
+
+func TestScrapeMetaHTTPRejectsNonHTML(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "image/png")
+		w.Write([]byte{0x89, 'P', 'N', 'G'})
+	}))
+	defer srv.Close()
+
+	if _, err := NewHTTPEngine(DefaultConfig()).ScrapeMeta(context.Background(), srv.URL, MetaOptions{}); err == nil {
+		t.Error("expected error for non-html content")
+	}
+}
+
+func TestHTTPEngineAcceptLanguage(t *testing.T) {
+	if got := acceptLanguage("ko-KR, ko,en"); got != "ko-KR,ko;q=0.9,en;q=0.8" {
+		t.Errorf("acceptLanguage = %q", got)
+	}
+
+	var header string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		header = r.Header.Get("Accept-Language")
+		w.Write([]byte(`<title>ok</title>`))
+	}))
+	defer srv.Close()
+	cfg := DefaultConfig()
+	cfg.Browser.Lang = "en-US,en"
+	if _, err := NewHTTPEngine(cfg).ScrapeMeta(context.Background(), srv.URL, MetaOptions{}); err != nil {
+		t.Fatal(err)
+	}
+	if header != "en-US,en;q=0.9" {
+		t.Errorf("Accept-Language = %q", header)
+	}
+}
@@ -0,0 +1,130 @@
+package internal
+
+import (
+	"context"
+	"net/url"
+	"strings"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+// Prometheus 지표. /metrics 에서 promhttp.Handler()로 내보낸다.
+var (
+	scrapeDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "scraper_scrape_duration_seconds",
+		Help:    "엔진 한 번의 스크래핑 시간",
+		Buckets: []float64{0.1, 0.25, 0.5, 1, 2.5, 5, 10, 15, 25, 40, 60},
+	}, []string{"engine", "extractor", "host"})
+
+	scrapeOutcomes = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "scraper_scrape_outcomes_total",
+		Help: "엔진별 스크래핑 결과 (ok, incomplete, 에러 코드)",
+	}, []string{"engine", "extractor", "code"})
+
+	emptyFields = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "scraper_empty_fields_total",
+		Help: "엔진이 비워 둔 결과 필드 수. 셀렉터가 깨졌는지 볼 때 쓴다",
+	}, []string{"engine", "extractor", "field"})
+
+	poolWait = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "scraper_pool_wait_seconds",
+		Help:    "브라우저 탭/세션을 빌리기까지 기다린 시간",
+		Buckets: []float64{0.001, 0.01, 0.05, 0.1, 0.5, 1, 2.5, 5, 10, 30},
+	}, []string{"engine"})
+
+	requestErrors = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "scraper_request_errors_total",
+		Help: "에러로 끝난 API 요청 수 (에러 코드별)",
+	}, []string{"code"})
+
+	coalescedTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "scraper_coalesced_total",
+		Help: "진행 중인 같은 요청에 합쳐진 호출 수",
+	}, []string{"type"})
+)
+
+// RegisterPoolGauges : 엔진 풀의 사용 중/열린 탭(세션) 수를 지표로 내보낸다.
+func RegisterPoolGauges(engine string, inUse, open func() int) {
+	labels := prometheus.Labels{"engine": engine}
+	promauto.NewGaugeFunc(prometheus.GaugeOpts{
+		Name:        "scraper_browser_sessions_in_use",
+		Help:        "빌려 간 브라우저 탭/세션 수",
+		ConstLabels: labels,
+	}, func() float64 { return float64(inUse()) })
+	promauto.NewGaugeFunc(prometheus.GaugeOpts{
+		Name:        "scraper_browser_sessions_open",
+		Help:        "열려 있는 브라우저 탭/세션 수 (쉬는 것 포함)",
+		ConstLabels: labels,
+	}, func() float64 { return float64(open()) })
+}
+
+// CountRequestError : API 응답으로 나간 에러를 센다.
+func CountRequestError(err *ScrapeError) {
+	requestErrors.WithLabelValues(string(err.Code)).Inc()
+}
+
+type extractorKey struct{}
+
+// extractorInfo : 지표에 붙일 추출기 이름과, URL에 맞은 추출기의 호스트 패턴
+type extractorInfo struct {
+	name, host string
+}
+
+// withExtractor : 지표에 붙일 추출기
+func withExtractor(ctx context.Context, ex Extractor, pageURL string) context.Context {
+	info := extractorInfo{name: ex.Name}
+	if u, err := url.Parse(CanonicalURL(pageURL)); err == nil {
+		info.host = hostPattern(u.Hostname(), ex.Patterns)
+	}
+	return context.WithValue(ctx, extractorKey{}, info)
+}
+
+// extractorLabel : Extract를 거치지 않은 요청(/scrape-twitter, /meta)은 결과 종류를 쓴다.
+func extractorLabel(ctx context.Context, typ string) string {
+	if info, ok := ctx.Value(extractorKey{}).(extractorInfo); ok {
+		return info.name
+	}
+	return typ
+}
+
+// otherHost : 추출기나 도메인 규칙에 없는 호스트의 라벨
+const otherHost = "other"
+
+// hostLabel : 호스트에 맞는 추출기 패턴이나 도메인 규칙 패턴. 둘 다 없으면 "other".
+// 클라이언트가 보낸 호스트를 그대로 쓰면 호스트마다 시계열이 생겨서 끝없이 늘어난다.
+func (c *Chain) hostLabel(ctx context.Context, pageURL string) string {
+	if info, ok := ctx.Value(extractorKey{}).(extractorInfo); ok && info.host != "" {
+		return info.host
+	}
+	u, err := url.Parse(CanonicalURL(pageURL))
+	if err != nil {
+		return otherHost
+	}
+	for _, rule := range c.policy.Rules {
+		if MatchHost(u.Hostname(), rule.Pattern) {
+			return strings.TrimLeft(rule.Pattern, "*.")
+		}
+	}
+	return otherHost
+}
+
+// hostPattern : host에 맞는 첫 패턴 (*. 제외). 없으면 빈 문자열
+func hostPattern(host string, patterns []string) string {
+	for _, p := range patterns {
+		if MatchHost(host, p) {
+			return strings.TrimLeft(p, "*.")
+		}
+	}
+	return ""
+}
+
+func emptyFieldNames(empty map[string]bool) []string {
+	var names []string
+	for name, isEmpty := range empty {
+		if isEmpty {
+			names = append(names, name)
+		}
+	}
+	return names
+}
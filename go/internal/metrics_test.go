@@ -0,0 +1,63 @@
+package internal
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+func TestChainMetrics(t *testing.T) {
+	registry := NewRegistry()
+	registry.Register(fakeScraper{name: "broken", err: errors.New("boom")})
+	registry.Register(fakeScraper{name: "partial", meta: &MetaData{Title: "title"}})
+	chain, err := NewChain(registry, ChainPolicy{Default: []string{"broken", "partial"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ex := Extractor{Name: "metrics-test", Type: TypeMeta}
+	if _, err := chain.Extract(context.Background(), "", "https://metrics.example/page", ex); err != nil {
+		t.Fatal(err)
+	}
+
+	if n := testutil.ToFloat64(scrapeOutcomes.WithLabelValues("broken", "metrics-test", string(CodeInternal))); n != 1 {
+		t.Errorf("broken outcomes = %v", n)
+	}
+	if n := testutil.ToFloat64(scrapeOutcomes.WithLabelValues("partial", "metrics-test", "ok")); n != 1 {
+		t.Errorf("ok outcomes = %v", n)
+	}
+	if n := testutil.ToFloat64(emptyFields.WithLabelValues("partial", "metrics-test", "description")); n != 1 {
+		t.Errorf("empty description = %v", n)
+	}
+	if n := testutil.ToFloat64(emptyFields.WithLabelValues("partial", "metrics-test", "title")); n != 0 {
+		t.Errorf("empty title = %v", n)
+	}
+	if n := testutil.CollectAndCount(scrapeDuration, "scraper_scrape_duration_seconds"); n < 2 {
+		t.Errorf("duration series = %d", n)
+	}
+}
+
+func TestHostLabel(t *testing.T) {
+	chain := &Chain{policy: ChainPolicy{Rules: []ChainRule{{Pattern: "*.notion.site"}}}}
+	tweet := Extractor{Name: "tweet", Patterns: []string{"x.com", "twitter.com"}}
+	page := Extractor{Name: "page"}
+	cases := []struct {
+		ctx  context.Context
+		url  string
+		want string
+	}{
+		{withExtractor(context.Background(), tweet, "https://mobile.twitter.com/a/status/1"), "https://mobile.twitter.com/a/status/1", "x.com"},
+		{withExtractor(context.Background(), page, "https://team.notion.site/page"), "https://team.notion.site/page", "notion.site"},
+		{context.Background(), "https://team.notion.site/page", "notion.site"},
+		// 규칙에 없는 호스트는 하나로 묶는다
+		{withExtractor(context.Background(), page, "https://random-123.example"), "https://random-123.example", "other"},
+		{context.Background(), "https://another-456.example", "other"},
+	}
+	for _, c := range cases {
+		if got := chain.hostLabel(c.ctx, c.url); got != c.want {
+			t.Errorf("hostLabel(%q) = %q, want %q", c.url, got, c.want)
+		}
+	}
+}
@@ -0,0 +1,159 @@
+package internal
+
+import (
+	"context"
+	"net/url"
+	"sync"
+	"time"
+)
+
+// maxIdleHosts : 기본 제한을 쓰는 호스트별 리미터를 이 수 이상 들고 있으면 쉬는 것부터 정리한다.
+const maxIdleHosts = 1000
+
+// DomainLimiter : 대상 도메인별로 초당 요청 수와 동시 스크래핑 수를 제한한다.
+// 자리가 날 때까지 queue 개까지 기다리게 하고, 넘으면 rate_limited 에러를 돌려준다.
+type DomainLimiter struct {
+	cfg RateLimitConfig
+
+	mu    sync.Mutex
+	hosts map[string]*hostLimiter // 도메인 규칙 pattern 또는 호스트 이름
+}
+
+func NewDomainLimiter(cfg RateLimitConfig) *DomainLimiter {
+	return &DomainLimiter{cfg: cfg, hosts: map[string]*hostLimiter{}}
+}
+
+// Acquire : pageURL의 도메인에 자리를 잡는다. 끝나면 돌려받은 release를 호출해야 한다.
+func (d *DomainLimiter) Acquire(ctx context.Context, pageURL string) (release func(), err error) {
+	key, limit := d.match(pageURL)
+	if limit.RPS <= 0 && limit.Concurrency <= 0 {
+		return func() {}, nil
+	}
+
+	d.mu.Lock()
+	l, ok := d.hosts[key]
+	if !ok {
+		if len(d.hosts) >= maxIdleHosts {
+			d.pruneLocked()
+		}
+		l = newHostLimiter(limit)
+		d.hosts[key] = l
+	}
+	d.mu.Unlock()
+	return l.acquire(ctx, key)
+}
+
+// match : 먼저 등록된 도메인 규칙이 우선한다. 규칙이 없으면 호스트마다 기본 제한을 따로 건다.
+func (d *DomainLimiter) match(pageURL string) (string, DomainLimit) {
+	host := pageURL
+	if u, err := url.Parse(CanonicalURL(pageURL)); err == nil {
+		host = u.Hostname()
+	}
+	for _, rule := range d.cfg.Domains {
+		if MatchHost(host, rule.Pattern) {
+			if rule.Queue <= 0 {
+				rule.Queue = d.cfg.Default.Queue
+			}
+			return rule.Pattern, rule
+		}
+	}
+	return host, d.cfg.Default
+}
+
+func (d *DomainLimiter) pruneLocked() {
+	for key, l := range d.hosts {
+		if l.idle() {
+			delete(d.hosts, key)
+		}
+	}
+}
+
+// hostLimiter : 토큰 버킷(rps, burst) + 동시 실행 슬롯
+type hostLimiter struct {
+	limit DomainLimit
+	slots chan struct{} // Concurrency가 0이면 nil
+
+	mu      sync.Mutex
+	tokens  float64
+	last    time.Time
+	waiting int // acquire 안에 있는 요청 수
+}
+
+func newHostLimiter(limit DomainLimit) *hostLimiter {
+	if limit.Burst <= 0 {
+		limit.Burst = 1
+	}
+	l := &hostLimiter{limit: limit, tokens: float64(limit.Burst), last: time.Now()}
+	if limit.Concurrency > 0 {
+		l.slots = make(chan struct{}, limit.Concurrency)
+	}
+	return l
+}
+
+func (l *hostLimiter) acquire(ctx context.Context, key string) (func(), error) {
+	l.mu.Lock()
+	if l.waiting >= l.limit.Queue {
+		l.mu.Unlock()
+		return nil, NewError(CodeRateLimited, "rate limited: too many pending requests for %s", key)
+	}
+	l.waiting++
+	delay := l.reserve()
+	l.mu.Unlock()
+	defer func() {
+		l.mu.Lock()
+		l.waiting--
+		l.mu.Unlock()
+	}()
+
+	if delay > 0 {
+		timer := time.NewTimer(delay)
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			timer.Stop()
+			l.unreserve()
+			return nil, ctx.Err()
+		}
+	}
+
+	if l.slots == nil {
+		return func() {}, nil
+	}
+	select {
+	case l.slots <- struct{}{}:
+		var once sync.Once
+		return func() { once.Do(func() { <-l.slots }) }, nil
+	case <-ctx.Done():
+		// 자리를 기다리다 취소해도 가져간 토큰은 쓰지 않았으니 돌려놓는다
+		l.unreserve()
+		return nil, ctx.Err()
+	}
+}
+
+// reserve : 토큰 하나를 가져가고, 부족하면 채워질 때까지 기다릴 시간을 돌려준다. l.mu를 잡고 호출한다.
+func (l *hostLimiter) reserve() time.Duration {
+	if l.limit.RPS <= 0 {
+		return 0
+	}
+	now := time.Now()
+	l.tokens = min(l.tokens+now.Sub(l.last).Seconds()*l.limit.RPS, float64(l.limit.Burst))
+	l.last = now
+	l.tokens--
+	if l.tokens >= 0 {
+		return 0
+	}
+	return time.Duration(-l.tokens / l.limit.RPS * float64(time.Second))
+}
+
+// unreserve : 기다리다(토큰 또는 동시 실행 자리) 취소한 요청의 토큰을 돌려놓는다.
+func (l *hostLimiter) unreserve() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.tokens++
+}
+
+func (l *hostLimiter) idle() bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.waiting == 0 && len(l.slots) == 0
+}
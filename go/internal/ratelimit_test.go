@@ -0,0 +1,103 @@
+package internal
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestDomainLimiterConcurrencyAndQueue(t *testing.T) {
+	d := NewDomainLimiter(RateLimitConfig{
+		Default: DomainLimit{Queue: 10},
+		Domains: []DomainLimit{{Pattern: "x.com", Concurrency: 1, Queue: 1}},
+	})
+	ctx := context.Background()
+
+	release, err := d.Acquire(ctx, "https://x.com/a/status/1")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// 두 번째는 자리를 기다린다 (queue 1)
+	waited := make(chan error, 1)
+	go func() {
+		r, err := d.Acquire(ctx, "https://twitter.com/a/status/2")
+		if err == nil {
+			r()
+		}
+		waited <- err
+	}()
+	time.Sleep(20 * time.Millisecond)
+
+	// 세 번째는 대기열이 차서 바로 실패한다
+	_, err = d.Acquire(ctx, "https://mobile.x.com/a/status/3")
+	var se *ScrapeError
+	if !errors.As(err, &se) || se.Code != CodeRateLimited || se.Status() != 429 {
+		t.Fatalf("expected rate_limited, got %v", err)
+	}
+
+	// 규칙이 없는 호스트는 제한을 받지 않는다
+	other, err := d.Acquire(ctx, "https://kre.pe/abc")
+	if err != nil {
+		t.Fatal(err)
+	}
+	other()
+
+	release()
+	if err := <-waited; err != nil {
+		t.Errorf("queued request failed: %v", err)
+	}
+}
+
+func TestDomainLimiterRPS(t *testing.T) {
+	d := NewDomainLimiter(RateLimitConfig{
+		Default: DomainLimit{RPS: 20, Burst: 1, Queue: 10},
+	})
+	ctx := context.Background()
+
+	start := time.Now()
+	for i := 0; i < 3; i++ {
+		release, err := d.Acquire(ctx, "https://example.com/page")
+		if err != nil {
+			t.Fatal(err)
+		}
+		release()
+	}
+	// 첫 요청은 바로, 나머지 둘은 50ms 간격
+	if elapsed := time.Since(start); elapsed < 90*time.Millisecond {
+		t.Errorf("3 requests at 20 rps took %v", elapsed)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Millisecond)
+	defer cancel()
+	d.Acquire(context.Background(), "https://example.com/page") // 토큰을 다 쓴다
+	if _, err := d.Acquire(ctx, "https://example.com/page"); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("expected deadline exceeded, got %v", err)
+	}
+}
+
+func TestHostLimiterReturnsTokenWhenSlotWaitCanceled(t *testing.T) {
+	l := newHostLimiter(DomainLimit{RPS: 1, Burst: 2, Concurrency: 1, Queue: 10})
+	release, err := l.acquire(context.Background(), "x.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// 토큰은 받았지만 자리를 기다리다 취소한다
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if _, err := l.acquire(ctx, "x.com"); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected deadline exceeded, got %v", err)
+	}
+	release()
+
+	// 돌려놓은 토큰으로 바로 들어간다 (1 rps라 토큰이 없으면 1초 가까이 기다려야 함)
+	ctx, cancel = context.WithTimeout(context.Background(), 200*time.Millisecond)
+	defer cancel()
+	if release, err := l.acquire(ctx, "x.com"); err != nil {
+		t.Fatalf("token not returned: %v", err)
+	} else {
+		release()
+	}
+}
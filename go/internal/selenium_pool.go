@@ -0,0 +1,312 @@
+package internal
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"sync"
+
+	"github.com/tebeka/selenium"
+)
+
+// Session : 풀에서 빌려준 WebDriver 세션
+type Session struct {
+	WD   selenium.WebDriver
+	uses int
+}
+
+// SeleniumPool : ChromeDriver 서비스 하나를 프로세스 수명 동안 유지하고
+// 그 위에 WebDriver 세션을 selenium.pool_size 개만큼 돌려 쓴다.
+// selenium.hub_urls를 지정하면 로컬 ChromeDriver 대신 원격 Grid에 라운드로빈으로 세션을 만든다.
+type SeleniumPool struct {
+	cfg  *Config
+	hubs *Endpoints // 원격 모드일 때만
+
+	lifeCtx    context.Context
+	lifeCancel context.CancelFunc
+
+	mu        sync.Mutex
+	restartMu sync.Mutex // ChromeDriver 재시작을 한 번에 하나씩
+	service   *selenium.Service
+	sessions  map[*Session]struct{} // 빌려준 세션 포함 전체. Close에서 모두 종료한다
+	closed    bool
+
+	idle  chan *Session
+	slots chan struct{}
+}
+
+func NewSeleniumPool(cfg *Config) *SeleniumPool {
+	lifeCtx, lifeCancel := context.WithCancel(context.Background())
+	p := &SeleniumPool{
+		cfg:        cfg,
+		lifeCtx:    lifeCtx,
+		lifeCancel: lifeCancel,
+		sessions:   map[*Session]struct{}{},
+		idle:       make(chan *Session, cfg.Selenium.PoolSize),
+		slots:      make(chan struct{}, cfg.Selenium.PoolSize),
+	}
+	if hubs := cfg.Selenium.HubURLs; len(hubs) > 0 {
+		p.hubs = NewEndpoints(hubs, CheckSeleniumHub)
+		go p.hubs.Run(lifeCtx, cfg.Timeouts.HealthCheck)
+		slog.Info("🌍 selenium using remote hubs", "hubs", hubs)
+	}
+	return p
+}
+
+// Lease : 쉬고 있는 세션을 빌린다. 없으면 새로 만들고, 한도에 걸리면 ctx가 끝날 때까지 기다린다.
+func (p *SeleniumPool) Lease(ctx context.Context) (*Session, error) {
+	if p.isClosed() {
+		return nil, ErrPoolClosed
+	}
+
+	select {
+	case s := <-p.idle:
+		return s, nil
+	default:
+	}
+
+	select {
+	case s := <-p.idle:
+		return s, nil
+	case p.slots <- struct{}{}:
+		s, err := p.newSession()
+		if err != nil {
+			<-p.slots
+			return nil, err
+		}
+		return s, nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// TryLease : 기다리지 않고 세션을 빌린다. 세션이 모두 일하는 중이면 (nil, nil)
+func (p *SeleniumPool) TryLease() (*Session, error) {
+	if p.isClosed() {
+		return nil, ErrPoolClosed
+	}
+	select {
+	case s := <-p.idle:
+		return s, nil
+	default:
+	}
+	select {
+	case s := <-p.idle:
+		return s, nil
+	case p.slots <- struct{}{}:
+		s, err := p.newSession()
+		if err != nil {
+			<-p.slots
+			return nil, err
+		}
+		return s, nil
+	default:
+		return nil, nil
+	}
+}
+
+// Alive : 세션을 빌리지 않고 ChromeDriver(원격이면 살아 있는 hub)가 응답하는지 본다.
+func (p *SeleniumPool) Alive(ctx context.Context) error {
+	if p.hubs != nil {
+		hub, err := p.hubs.Next()
+		if err != nil {
+			return err
+		}
+		return CheckSeleniumHub(ctx, hub)
+	}
+	return httpHealthCheck(ctx, fmt.Sprintf("http://localhost:%d/status", p.cfg.Selenium.Port))
+}
+
+// Release : 세션을 돌려준다. 스크래핑 중 에러가 났는데 세션이 죽었거나
+// MaxUses를 넘긴 세션은 종료하고 빈 자리를 만든다.
+func (p *SeleniumPool) Release(s *Session, scrapeErr error) {
+	s.uses++
+	switch {
+	case p.isClosed():
+		p.Discard(s)
+		return
+	case s.uses >= p.cfg.Selenium.MaxUses:
+		slog.Info("♻️ Recycling WebDriver session", "uses", s.uses)
+		p.Discard(s)
+		return
+	case scrapeErr != nil && !sessionAlive(s.WD):
+		slog.Warn("♻️ Recycling crashed WebDriver session", "err", scrapeErr)
+		p.Discard(s)
+		return
+	}
+
+	// 다음 요청에 쿠키가 남지 않게 정리
+	if err := s.WD.DeleteAllCookies(); err != nil {
+		p.Discard(s)
+		return
+	}
+	if err := s.WD.Get("about:blank"); err != nil {
+		p.Discard(s)
+		return
+	}
+
+	select {
+	case p.idle <- s:
+	default:
+		p.Discard(s)
+	}
+}
+
+// InUse : 빌려 간 세션 수
+func (p *SeleniumPool) InUse() int { return len(p.slots) - len(p.idle) }
+
+// Open : 열려 있는 세션 수 (쉬는 세션 포함)
+func (p *SeleniumPool) Open() int { return len(p.slots) }
+
+// Discard : 세션을 종료하고 풀에서 뺀다.
+func (p *SeleniumPool) Discard(s *Session) {
+	p.mu.Lock()
+	_, owned := p.sessions[s]
+	delete(p.sessions, s)
+	p.mu.Unlock()
+	if owned {
+		_ = s.WD.Quit()
+	}
+	<-p.slots
+}
+
+// Close : 빌려준 세션까지 모든 세션과 ChromeDriver 서비스를 종료한다.
+func (p *SeleniumPool) Close() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.closed {
+		return
+	}
+	p.closed = true
+	p.lifeCancel()
+
+	for s := range p.sessions {
+		_ = s.WD.Quit()
+		delete(p.sessions, s)
+	}
+	if p.service != nil {
+		_ = p.service.Stop()
+		p.service = nil
+	}
+	slog.Info("🧹 selenium pool closed")
+}
+
+func (p *SeleniumPool) isClosed() bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.closed
+}
+
+// newSession : 세션을 만들고 Close에서 정리할 수 있게 기록해 둔다.
+func (p *SeleniumPool) newSession() (*Session, error) {
+	s, err := p.dialSession()
+	if err != nil {
+		return nil, err
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.closed {
+		_ = s.WD.Quit()
+		return nil, ErrPoolClosed
+	}
+	p.sessions[s] = struct{}{}
+	return s, nil
+}
+
+// dialSession : 원격 모드에서는 실패한 허브를 제외하고 다음 허브로 다시 시도한다.
+func (p *SeleniumPool) dialSession() (*Session, error) {
+	if p.hubs == nil {
+		return p.newLocalSession()
+	}
+	var lastErr error
+	for i := 0; i < p.hubs.Len(); i++ {
+		hubURL, err := p.hubs.Next()
+		if err != nil {
+			return nil, err
+		}
+		wd, err := newWebDriver(p.cfg, hubURL)
+		if err == nil {
+			return &Session{WD: wd}, nil
+		}
+		lastErr = err
+		p.hubs.MarkDown(hubURL)
+	}
+	return nil, lastErr
+}
+
+// newLocalSession : 로컬 ChromeDriver에 세션을 만든다. 실패하면 서비스 상태(/status)를 확인해서
+// 서비스가 죽었을 때만 재시작하고 한 번 더 시도한다. 세션 하나가 실패했다고 다른 세션까지 끊지 않는다.
+func (p *SeleniumPool) newLocalSession() (*Session, error) {
+	hubURL, service, err := p.ensureService()
+	if err != nil {
+		return nil, err
+	}
+	wd, err := newWebDriver(p.cfg, hubURL)
+	if err == nil {
+		return &Session{WD: wd}, nil
+	}
+	if hubURL, err = p.restartService(service, err); err != nil {
+		return nil, err
+	}
+	if wd, err = newWebDriver(p.cfg, hubURL); err != nil {
+		return nil, err
+	}
+	return &Session{WD: wd}, nil
+}
+
+// ensureService : ChromeDriver 서비스가 떠 있는지 확인하고 hub URL과 지금 서비스를 돌려준다.
+func (p *SeleniumPool) ensureService() (string, *selenium.Service, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.closed {
+		return "", nil, ErrPoolClosed
+	}
+	if p.service == nil {
+		service, err := startChromeDriverService(p.cfg)
+		if err != nil {
+			return "", nil, err
+		}
+		p.service = service
+		slog.Info("🚗 ChromeDriver started", "port", p.cfg.Selenium.Port)
+	}
+	return p.hubURL(), p.service, nil
+}
+
+// restartService : 세션 생성이 실패한 뒤 호출한다. 재시작은 한 번에 하나씩만 하고,
+// 그 사이 다른 요청이 이미 재시작했으면 새 서비스를 그대로 쓴다.
+// 서비스가 /status에 응답하면 서비스 문제가 아니므로 세션 에러(dialErr)를 그대로 돌려준다.
+func (p *SeleniumPool) restartService(failed *selenium.Service, dialErr error) (string, error) {
+	p.restartMu.Lock()
+	defer p.restartMu.Unlock()
+
+	p.mu.Lock()
+	current := p.service
+	p.mu.Unlock()
+	if current != failed {
+		return p.hubURL(), nil
+	}
+	// ChromeDriver는 url-base 없이 떠 있으므로 /status는 루트에 있다
+	if err := httpHealthCheck(p.lifeCtx, fmt.Sprintf("http://localhost:%d/status", p.cfg.Selenium.Port)); err == nil {
+		return "", dialErr
+	}
+
+	slog.Warn("⚠️ Restarting ChromeDriver", "err", dialErr)
+	p.mu.Lock()
+	if p.service == failed && p.service != nil {
+		_ = p.service.Stop()
+		p.service = nil
+	}
+	p.mu.Unlock()
+	hubURL, _, err := p.ensureService()
+	return hubURL, err
+}
+
+func (p *SeleniumPool) hubURL() string {
+	return fmt.Sprintf("http://localhost:%d/wd/hub", p.cfg.Selenium.Port)
+}
+
+// sessionAlive : 세션이 응답하는지 확인
+func sessionAlive(wd selenium.WebDriver) bool {
+	_, err := wd.CurrentURL()
+	return err == nil
+}
@@ -0,0 +1,191 @@
+package internal
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"net/url"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// threadItemsJS : 지금 화면에 그려진 트윗(article)들을 순서대로 읽는다.
+// 작성자와 트윗 ID는 시각(<time>)을 감싼 /handle/status/ID 링크에서 꺼낸다.
+var threadItemsJS = tweetJS(`
+	return JSON.stringify(Array.from(document.querySelectorAll('article')).map(a => {
+		const permalink = Array.from(a.querySelectorAll('a[href*="/status/"]')).find(l => l.querySelector('time'));
+		const m = permalink ? (permalink.getAttribute('href') || '').match(/^\/([^\/]+)\/status\/(\d+)/) : null;
+		const q = quoteCard(a);
+		return {
+			id: m ? m[2] : '',
+			handle: m ? m[1] : '',
+			tweet: readTweet(a, q),
+			quoted: q ? readTweet(q, null) : null,
+			retweeted_by: retweetedBy(a),
+		};
+	}));
+`)
+
+// threadScrollJS : 다음 답글들이 그려지도록 한 화면 내린다.
+const threadScrollJS = `window.scrollBy(0, window.innerHeight)`
+
+// threadItem : threadItemsJS 결과 한 줄
+type threadItem struct {
+	ID          string     `json:"id"`
+	Handle      string     `json:"handle"`
+	Tweet       TweetData  `json:"tweet"`
+	Quoted      *TweetData `json:"quoted"`
+	RetweetedBy string     `json:"retweeted_by"`
+}
+
+// tweet : 답글 한 개를 TweetData로 바꾼다. traffic은 네트워크에서 본 동영상 스트림 주소
+func (it threadItem) tweet(traffic []string) TweetData {
+	t := it.Tweet
+	t.ID, t.Handle = it.ID, it.Handle
+	t.Text = strings.ReplaceAll(t.Text, "\n", " ")
+	t.PostedAt = normalizePostedAt(t.PostedAt)
+	t.Media = attachVariants(t.Media, traffic)
+	if it.Quoted != nil {
+		quoted := *it.Quoted
+		quoted.Text = strings.ReplaceAll(quoted.Text, "\n", " ")
+		quoted.PostedAt = normalizePostedAt(quoted.PostedAt)
+		quoted.Media = attachVariants(quoted.Media, traffic)
+		t.Quoted = &quoted
+	}
+	t.RetweetedBy = it.RetweetedBy
+	return t
+}
+
+// statusURLRe : /handle/status/123 형태의 트윗 주소
+var statusURLRe = regexp.MustCompile(`^/([A-Za-z0-9_]+)/status/(\d+)`)
+
+// parseStatusURL : 트윗 주소에서 작성자 핸들과 트윗 ID를 꺼낸다.
+func parseStatusURL(rawURL string) (handle, id string, ok bool) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return "", "", false
+	}
+	m := statusURLRe.FindStringSubmatch(u.Path)
+	if m == nil {
+		return "", "", false
+	}
+	return m[1], m[2], true
+}
+
+// threadWalker : 스크롤하면서 읽은 트윗들을 순서대로 합친다.
+// X는 화면에서 멀어진 트윗을 DOM에서 지우기 때문에 한 번에 전체를 읽을 수 없다.
+type threadWalker struct {
+	focalID, author string
+	items           []threadItem
+	seen            map[string]bool
+}
+
+func newThreadWalker(tweetURL string) *threadWalker {
+	author, id, _ := parseStatusURL(tweetURL)
+	return &threadWalker{focalID: id, author: author, seen: map[string]bool{}}
+}
+
+// add : 새로 본 트윗만 이어 붙이고, 새 트윗이 있었는지 돌려준다.
+func (w *threadWalker) add(items []threadItem) bool {
+	added := false
+	for _, it := range items {
+		if it.ID == "" || w.seen[it.ID] {
+			continue
+		}
+		w.seen[it.ID] = true
+		w.items = append(w.items, it)
+		added = true
+	}
+	return added
+}
+
+// replies : 기준 트윗 바로 뒤에 이어진 작성자 본인의 답글들 (최대 depth개).
+// 다른 사람의 트윗이 나오면 타래가 끝난 것으로 보고 done을 돌려준다.
+func (w *threadWalker) replies(depth int) (replies []threadItem, done bool) {
+	start := -1
+	for i, it := range w.items {
+		if it.ID == w.focalID {
+			start = i
+			// 페이지에 표시된 핸들이 정규 표기(대소문자)다
+			w.author = it.Handle
+			break
+		}
+	}
+	if start < 0 {
+		return nil, false
+	}
+	for _, it := range w.items[start+1:] {
+		if !strings.EqualFold(it.Handle, w.author) {
+			return replies, true
+		}
+		replies = append(replies, it)
+		if len(replies) >= depth {
+			return replies, true
+		}
+	}
+	return replies, false
+}
+
+// threadScrollLimit : 새 트윗이 더 나오지 않을 때 몇 번까지 더 내려 볼지
+const threadScrollLimit = 2
+
+// threadScrollWait : 내린 뒤 답글이 그려지기를 기다리는 시간
+var threadScrollWait = 700 * time.Millisecond
+
+// collectThread : read로 화면의 트윗을 읽고 scroll로 내리기를 반복해서 작성자의 답글 타래를 모은다.
+// traffic은 다 내린 뒤 한 번 불러서 답글 동영상/GIF에 스트림 주소를 붙인다.
+// 중간에 실패하면 그때까지 모은 답글과 에러를 함께 돌려준다.
+func collectThread(ctx context.Context, tweetURL string, depth int, read func() ([]threadItem, error), scroll func() error, traffic func() []string) ([]TweetData, error) {
+	ctx, span := StartSpan(ctx, "extract.thread")
+	w := newThreadWalker(tweetURL)
+	var (
+		replies []threadItem
+		err     error
+	)
+	for idle := 0; idle <= threadScrollLimit; {
+		var items []threadItem
+		if items, err = read(); err != nil {
+			break
+		}
+		if !w.add(items) {
+			idle++
+		} else {
+			idle = 0
+		}
+		var done bool
+		if replies, done = w.replies(depth); done {
+			break
+		}
+		if err = scroll(); err != nil {
+			break
+		}
+		select {
+		case <-ctx.Done():
+			err = ctx.Err()
+		case <-time.After(threadScrollWait):
+		}
+		if err != nil {
+			break
+		}
+	}
+	EndSpan(span, err)
+
+	var urls []string
+	if len(replies) > 0 {
+		urls = traffic()
+	}
+	thread := make([]TweetData, 0, len(replies))
+	for _, it := range replies {
+		thread = append(thread, it.tweet(urls))
+	}
+	slog.DebugContext(ctx, "🧵 Thread collected", "replies", len(thread))
+	return thread, err
+}
+
+// decodeThreadItems : threadItemsJS 결과(JSON 문자열)를 읽는다.
+func decodeThreadItems(raw string) ([]threadItem, error) {
+	var items []threadItem
+	err := json.Unmarshal([]byte(raw), &items)
+	return items, err
+}
@@ -0,0 +1,173 @@
+package internal
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestParseStatusURL(t *testing.T) {
+	cases := []struct {
+		in, handle, id string
+		ok             bool
+	}{
+		{"https://x.com/Naeng2_/status/123?s=20", "Naeng2_", "123", true},
+		{"https://x.com/naeng2_/status/123/photo/1", "naeng2_", "123", true},
+		{"https://x.com/naeng2_", "", "", false},
+		{"https://x.com/i/flow/login", "", "", false},
+	}
+	for _, c := range cases {
+		handle, id, ok := parseStatusURL(c.in)
+		if handle != c.handle || id != c.id || ok != c.ok {
+			t.Errorf("parseStatusURL(%q) = %q, %q, %v", c.in, handle, id, ok)
+		}
+	}
+}
+
+func item(id, handle string) threadItem {
+	return threadItem{ID: id, Handle: handle, Tweet: TweetData{Text: "tweet " + id, Username: "@" + handle}}
+}
+
+func TestThreadWalker(t *testing.T) {
+	w := newThreadWalker("https://x.com/artist/status/2")
+	// 스크롤하면서 앞쪽 트윗은 DOM에서 사라지고 겹치는 부분이 있다
+	w.add([]threadItem{item("1", "someone"), item("2", "Artist"), item("3", "Artist")})
+	if replies, done := w.replies(10); done || len(replies) != 1 {
+		t.Fatalf("first window: %v, done=%v", replies, done)
+	}
+	w.add([]threadItem{item("3", "Artist"), item("4", "artist"), item("5", "fan"), item("6", "Artist")})
+	replies, done := w.replies(10)
+	if !done || len(replies) != 2 || replies[0].ID != "3" || replies[1].ID != "4" {
+		t.Fatalf("replies = %v, done=%v", replies, done)
+	}
+
+	if replies, done := w.replies(1); !done || len(replies) != 1 {
+		t.Errorf("depth 1: %v, done=%v", replies, done)
+	}
+}
+
+func TestCollectThread(t *testing.T) {
+	defer func(wait time.Duration) { threadScrollWait = wait }(threadScrollWait)
+	threadScrollWait = 0
+	gif := item("4", "artist")
+	gif.Tweet.Media = []MediaItem{{Type: "animated_gif", URL: "https://pbs.twimg.com/tweet_video_thumb/GmqAbC.jpg"}}
+	windows := [][]threadItem{
+		{item("2", "artist"), item("3", "artist")},
+		{item("3", "artist"), gif},
+		{item("4", "artist"), item("5", "fan")},
+	}
+	reads, scrolls := 0, 0
+	read := func() ([]threadItem, error) {
+		w := windows[min(reads, len(windows)-1)]
+		reads++
+		return w, nil
+	}
+	scroll := func() error { scrolls++; return nil }
+	traffic := func() []string { return []string{"https://video.twimg.com/tweet_video/GmqAbC.mp4"} }
+
+	thread, err := collectThread(context.Background(), "https://x.com/artist/status/2", 5, read, scroll, traffic)
+	if err != nil || len(thread) != 2 || thread[0].Text != "tweet 3" || thread[1].Username != "@artist" {
+		t.Fatalf("thread = %+v, %v", thread, err)
+	}
+	// 답글의 GIF에도 네트워크에서 본 스트림 주소가 붙는다
+	if m := thread[1].Media; len(m) != 1 || len(m[0].Variants) != 1 {
+		t.Errorf("reply media = %+v", m)
+	}
+	if scrolls != 2 {
+		t.Errorf("scrolled %d times, want 2", scrolls)
+	}
+
+	// 읽다가 실패하면 그때까지 모은 답글을 돌려준다
+	reads = 0
+	boom := errors.New("boom")
+	thread, err = collectThread(context.Background(), "https://x.com/artist/status/2", 5, func() ([]threadItem, error) {
+		if reads > 0 {
+			return nil, boom
+		}
+		reads++
+		return windows[0], nil
+	}, scroll, traffic)
+	if !errors.Is(err, boom) || len(thread) != 1 {
+		t.Errorf("thread = %+v, %v", thread, err)
+	}
+}
+
+func TestChainCachesThreadSeparately(t *testing.T) {
+	r := NewRegistry()
+	r.Register(threadScraper{})
+	c, err := NewChain(r, ChainPolicy{Default: []string{"thread"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	cache, _ := NewCache(CacheConfig{Size: 10, TweetTTL: time.Hour})
+	c.UseCache(cache)
+
+	ctx := context.Background()
+	if _, err := c.ScrapeTweet(ctx, "", "https://x.com/a/status/1", TweetOptions{}); err != nil {
+		t.Fatal(err)
+	}
+	data, err := c.ScrapeTweet(ctx, "", "https://x.com/a/status/1", TweetOptions{Thread: 3})
+	if err != nil || data.CacheHit || len(data.Thread) != 3 {
+		t.Errorf("thread = %+v, %v", data, err)
+	}
+}
+
+func TestChainDoesNotCacheCutThread(t *testing.T) {
+	r := NewRegistry()
+	r.Register(threadScraper{cut: true})
+	r.Register(fakeScraper{name: "selenium", tweet: &TweetData{Text: "full"}})
+	c, err := NewChain(r, ChainPolicy{Default: []string{"thread", "selenium"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	cache, _ := NewCache(CacheConfig{Size: 10, TweetTTL: time.Hour})
+	c.UseCache(cache)
+
+	ctx := context.Background()
+	for i := 0; i < 2; i++ {
+		data, err := c.ScrapeTweet(ctx, "", "https://x.com/a/status/1", TweetOptions{Thread: 4})
+		// 끊긴 타래도 그대로 돌려주고 다음 엔진으로 넘어가지 않는다. 캐시에는 넣지 않는다
+		if err != nil || data.CacheHit || !data.ThreadCut || len(data.Thread) != 2 || data.Engine != "thread" {
+			t.Errorf("call %d: thread = %+v, %v", i, data, err)
+		}
+	}
+}
+
+// threadScraper : opts.Thread 만큼 답글을 붙여 주는 엔진. cut이면 중간에 끊긴 타래를 돌려준다
+type threadScraper struct{ cut bool }
+
+func (threadScraper) Name() string { return "thread" }
+func (s threadScraper) ScrapeTweet(ctx context.Context, url string, opts TweetOptions) (*TweetData, error) {
+	if s.cut {
+		return &TweetData{Text: "root", Thread: make([]TweetData, opts.Thread/2), ThreadCut: true}, nil
+	}
+	return &TweetData{Text: "root", Thread: make([]TweetData, opts.Thread)}, nil
+}
+func (threadScraper) ScrapeMeta(ctx context.Context, url string, opts MetaOptions) (*MetaData, error) {
+	return nil, ErrUnsupported
+}
+
+func TestThreadJSON(t *testing.T) {
+	data := TweetData{
+		Text:      "root",
+		Thread:    []TweetData{threadItem{ID: "2", Handle: "artist", Tweet: TweetData{Text: "reply"}}.tweet(nil)},
+		CacheInfo: CacheInfo{FetchedAt: time.Now()},
+	}
+	raw, _ := json.Marshal(data)
+	var got struct {
+		FetchedAt string           `json:"fetched_at"`
+		Thread    []map[string]any `json:"thread"`
+	}
+	json.Unmarshal(raw, &got)
+	// 답글에는 가져온 시각이나 캐시 정보가 없다
+	if got.FetchedAt == "" || len(got.Thread) != 1 {
+		t.Fatalf("json = %s", raw)
+	}
+	for _, field := range []string{"fetched_at", "cache_hit"} {
+		if _, ok := got.Thread[0][field]; ok {
+			t.Errorf("reply has %s: %s", field, raw)
+		}
+	}
+}
@@ -0,0 +1,107 @@
+package internal
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/chromedp/chromedp"
+	"github.com/tebeka/selenium"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const tracerName = "github.com/einys/cmsn-scraper"
+
+// SetupTracing : tracing.exporter 에 맞는 TracerProvider를 전역으로 등록한다.
+// 돌려받은 함수는 종료할 때 호출해서 남은 span을 내보낸다. exporter가 none이면 아무것도 하지 않는다.
+func SetupTracing(ctx context.Context, cfg TracingConfig) (func(context.Context) error, error) {
+	var (
+		exporter sdktrace.SpanExporter
+		err      error
+	)
+	switch cfg.Exporter {
+	case "otlp":
+		var opts []otlptracehttp.Option
+		if cfg.Endpoint != "" {
+			opts = append(opts, otlptracehttp.WithEndpointURL(cfg.Endpoint))
+		}
+		exporter, err = otlptracehttp.New(ctx, opts...)
+	case "stdout":
+		exporter, err = stdouttrace.New(stdouttrace.WithWriter(os.Stdout), stdouttrace.WithPrettyPrint())
+	default:
+		return func(context.Context) error { return nil }, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("create %s trace exporter: %w", cfg.Exporter, err)
+	}
+
+	res, err := resource.New(ctx,
+		resource.WithAttributes(semconv.ServiceName(cfg.ServiceName)),
+		resource.WithFromEnv(),
+		resource.WithTelemetrySDK(),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("create trace resource: %w", err)
+	}
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(res),
+		sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.TraceIDRatioBased(cfg.SampleRatio))),
+	)
+	otel.SetTracerProvider(provider)
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(propagation.TraceContext{}, propagation.Baggage{}))
+	return provider.Shutdown, nil
+}
+
+// Tracer : 전역 TracerProvider의 tracer. 설정하지 않았으면 아무것도 기록하지 않는다.
+func Tracer() trace.Tracer { return otel.Tracer(tracerName) }
+
+// StartSpan : 내부 span을 시작한다.
+func StartSpan(ctx context.Context, name string, attrs ...attribute.KeyValue) (context.Context, trace.Span) {
+	return Tracer().Start(ctx, name, trace.WithAttributes(attrs...))
+}
+
+// EndSpan : 에러가 있으면 span에 남기고 끝낸다.
+func EndSpan(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
+
+// tracedAction : chromedp 단계 하나(이동, 추출 ...)를 span으로 감싼다.
+func tracedAction(name string, actions ...chromedp.Action) chromedp.Action {
+	return chromedp.ActionFunc(func(ctx context.Context) error {
+		ctx, span := StartSpan(ctx, name)
+		err := chromedp.Tasks(actions).Do(ctx)
+		EndSpan(span, err)
+		return err
+	})
+}
+
+// findElement : span을 남기는 selenium FindElement
+func findElement(ctx context.Context, wd selenium.WebDriver, by, value string) (selenium.WebElement, error) {
+	_, span := StartSpan(ctx, "selenium.FindElement", attribute.String("selenium.by", by), attribute.String("selenium.value", value))
+	elem, err := wd.FindElement(by, value)
+	EndSpan(span, err)
+	return elem, err
+}
+
+// findElements : span을 남기는 selenium FindElements
+func findElements(ctx context.Context, wd selenium.WebDriver, by, value string) ([]selenium.WebElement, error) {
+	_, span := StartSpan(ctx, "selenium.FindElements", attribute.String("selenium.by", by), attribute.String("selenium.value", value))
+	elems, err := wd.FindElements(by, value)
+	span.SetAttributes(attribute.Int("selenium.count", len(elems)))
+	EndSpan(span, err)
+	return elems, err
+}
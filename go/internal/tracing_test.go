@@ -0,0 +1,102 @@
+package internal
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/chromedp/chromedp"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// recordSpans : 테스트 동안 끝난 span을 모아 두는 TracerProvider를 전역으로 쓴다.
+func recordSpans(t *testing.T) *tracetest.SpanRecorder {
+	t.Helper()
+	recorder := tracetest.NewSpanRecorder()
+	prev := otel.GetTracerProvider()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+	t.Cleanup(func() { otel.SetTracerProvider(prev) })
+	return recorder
+}
+
+func spanAttr(s sdktrace.ReadOnlySpan, key attribute.Key) string {
+	for _, kv := range s.Attributes() {
+		if kv.Key == key {
+			return kv.Value.Emit()
+		}
+	}
+	return ""
+}
+
+func TestChainSpans(t *testing.T) {
+	recorder := recordSpans(t)
+	registry := NewRegistry()
+	registry.Register(&countingScraper{})
+	chain, err := NewChain(registry, ChainPolicy{Default: []string{"counting"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, parent := StartSpan(context.Background(), "request")
+	if _, err := chain.ScrapeMeta(ctx, "", "https://example.com/a", MetaOptions{}); err != nil {
+		t.Fatal(err)
+	}
+	parent.End()
+
+	var found bool
+	for _, s := range recorder.Ended() {
+		if s.Name() != "engine.scrape" {
+			continue
+		}
+		found = true
+		if s.Parent().SpanID() != parent.SpanContext().SpanID() {
+			t.Error("engine.scrape is not a child of the request span")
+		}
+		if got := spanAttr(s, "scraper.engine"); got != "counting" {
+			t.Errorf("scraper.engine = %q", got)
+		}
+	}
+	if !found {
+		t.Fatal("no engine.scrape span recorded")
+	}
+}
+
+func TestTracedAction(t *testing.T) {
+	recorder := recordSpans(t)
+	boom := errors.New("boom")
+	action := tracedAction("extract.text", chromedp.ActionFunc(func(ctx context.Context) error {
+		if !trace.SpanFromContext(ctx).SpanContext().IsValid() {
+			t.Error("step does not run inside its span")
+		}
+		return boom
+	}))
+	if err := action.Do(context.Background()); !errors.Is(err, boom) {
+		t.Fatalf("err = %v", err)
+	}
+
+	spans := recorder.Ended()
+	if len(spans) != 1 || spans[0].Name() != "extract.text" || spans[0].Status().Code != codes.Error {
+		t.Fatalf("spans = %+v", spans)
+	}
+}
+
+func TestBindContextCarriesSpan(t *testing.T) {
+	recordSpans(t)
+	req, span := StartSpan(WithRequestID(context.Background(), "req-1"), "request")
+	defer span.End()
+
+	ctx, cancel := bindContext(req, context.Background(), time.Second)
+	defer cancel()
+	if trace.SpanFromContext(ctx).SpanContext().SpanID() != span.SpanContext().SpanID() {
+		t.Error("tab context lost the request span")
+	}
+	if RequestID(ctx) != "req-1" {
+		t.Errorf("request id = %q", RequestID(ctx))
+	}
+}
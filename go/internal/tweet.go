@@ -1,8 +1,10 @@
 package internal
 
 import (
+	"context"
+	"encoding/json"
 	"fmt"
-	"log"
+	"log/slog"
 	"os"
 	"regexp"
 	"strings"
@@ -12,43 +14,231 @@ import (
 )
 
 type TweetData struct {
-	Text           string   `json:"text"`
-	Images         []string `json:"images"`
-	Username       string   `json:"username"`
-	UserNickname   string   `json:"user_nickname"`
-	UserProfileImg string   `json:"user_profile_img"`
-	MetaTag        string   `json:"meta_tag"`
-	Links          []string `json:"links"`
+	ID             string      `json:"id,omitempty"`        // 트윗 ID (최종 주소의 /status/ID)
+	Handle         string      `json:"handle,omitempty"`    // 작성자 핸들 (최종 주소 기준, @ 없이)
+	PostedAt       string      `json:"posted_at,omitempty"` // 올린 시각 (RFC3339)
+	Text           string      `json:"text"`
+	Images         []string    `json:"images"` // 사진 주소만 (예전 응답 호환). 동영상/GIF까지 보려면 Media
+	Media          []MediaItem `json:"media,omitempty"`
+	Username       string      `json:"username"`
+	UserNickname   string      `json:"user_nickname"`
+	UserProfileImg string      `json:"user_profile_img"`
+	MetaTag        string      `json:"meta_tag"`
+	Links          []string    `json:"links"`
+	Stats          *TweetStats `json:"stats,omitempty"`
+	Quoted         *TweetData  `json:"quoted,omitempty"`       // 인용한 트윗. 바깥 트윗의 본문/이미지/링크와 섞지 않는다
+	RetweetedBy    string      `json:"retweeted_by,omitempty"` // 재게시한 사람 (@handle). 화면에 재게시 표시가 있을 때만
+	Thread         []TweetData `json:"thread,omitempty"`       // ?thread=1 : 작성자가 이어서 단 답글들 (순서대로)
+	ThreadCut      bool        `json:"thread_cut,omitempty"`   // 타래를 모으다 에러(시간 초과 등)로 멈춤. Thread는 거기까지만
+	Engine         string      `json:"engine,omitempty"`       // 결과를 만든 엔진
+	CacheInfo
 }
 
-func ScrapeTweet(wd selenium.WebDriver, url string) (*TweetData, error) {
-	log.Printf("📥 Scraping tweet: %s", url)
-	if err := wd.Get(url); err != nil {
+// complete : 본문이나 사진/동영상이 있으면 완전한 결과로 본다.
+func (t *TweetData) complete() bool {
+	return t.Text != "" || len(t.Images) > 0 || len(t.Media) > 0
+}
+
+// cacheable : 완전한 결과라도 타래가 중간에 끊겼으면 캐시하지 않는다 (다음 엔진으로 넘어가지는 않음).
+func (t *TweetData) cacheable() bool { return t.complete() && !t.ThreadCut }
+
+// emptyFields : 비어 있는 필드 이름 (지표용)
+func (t *TweetData) emptyFields() []string {
+	return emptyFieldNames(map[string]bool{
+		"text":             t.Text == "",
+		"images":           len(t.Images) == 0,
+		"media":            len(t.Media) == 0,
+		"username":         t.Username == "",
+		"user_nickname":    t.UserNickname == "",
+		"user_profile_img": t.UserProfileImg == "",
+		"posted_at":        t.PostedAt == "",
+		"stats":            t.Stats == nil,
+	})
+}
+
+// tweetStateJS : 트윗 페이지가 어떤 상태인지 판별한다.
+// article(정상) | notfound(삭제/없는 트윗) | login(로그인 요구) | blocked(차단/오류 화면) | ""(아직 로딩 중)
+const tweetStateJS = `(function(){
+	if (document.querySelector('article')) return 'article';
+	const path = location.pathname;
+	if (path.startsWith('/i/flow/login') || path === '/login') return 'login';
+	const text = document.body ? document.body.innerText : '';
+	if (/this post is unavailable|this page doesn.t exist|this post was deleted|account doesn.t exist|게시물을 볼 수 없|페이지가 존재하지 않|삭제된 게시물|계정이 존재하지 않/i.test(text)) return 'notfound';
+	if (/sign in to x|log in to x|something went wrong|x에 로그인|문제가 발생했습니다/i.test(text)) return 'blocked';
+	return '';
+})()`
+
+// tweetLibJS : 트윗 화면을 읽는 공통 함수들. 인용 카드(quote) 안의 요소는 바깥 트윗 것으로 세지 않는다.
+// own(sel)은 첫 article에서 인용 카드를 뺀 요소, readTweet은 TweetData JSON 모양으로 트윗 하나를 읽는다.
+const tweetLibJS = `
+	function quoteCard(article) {
+		return Array.from(article.querySelectorAll('div[role="link"]'))
+			.find(d => d.querySelector('[data-testid="User-Name"]')) || null;
+	}
+	function scoped(root, skip, sel) {
+		return root ? Array.from(root.querySelectorAll(sel)).filter(el => !skip || !skip.contains(el)) : [];
+	}
+	function tweetLinks(anchors) {
+		const out = new Set();
+		for (const a of anchors) {
+			const href = a.getAttribute('href') || '';
+			if (href.startsWith('http')) out.add(href);
+			else if (href.startsWith('/')) out.add('https://x.com' + href);
+			const m = (a.textContent || '').trim().match(/[a-zA-Z]+:\/\/[^\s]+/);
+			if (m) out.add(m[0]);
+		}
+		return Array.from(out);
+	}
+	function retweetedBy(article) {
+		const label = article.querySelector('[data-testid="socialContext"]');
+		const link = label && label.closest('a[href]');
+		if (!link || !/repost|retweet|재게시|리트윗|리포스트/i.test(label.textContent || '')) return '';
+		return '@' + (link.getAttribute('href') || '').replace(/^\//, '');
+	}
+	function readMedia(all) {
+		return all('img[src*="pbs.twimg.com/media"], video').map(el => {
+			if (el.tagName === 'IMG') {
+				const alt = el.getAttribute('alt') || '';
+				return {
+					type: 'photo',
+					url: el.src,
+					width: el.naturalWidth || 0,
+					height: el.naturalHeight || 0,
+					alt: /^(image|이미지)$/i.test(alt) ? '' : alt,
+				};
+			}
+			const poster = el.getAttribute('poster') || '';
+			const sources = [el.currentSrc, el.getAttribute('src'), ...Array.from(el.querySelectorAll('source')).map(s => s.src)]
+				.filter(u => /^https?:/.test(u || ''));
+			return {
+				type: /tweet_video/.test(poster + sources.join(' ')) ? 'animated_gif' : 'video',
+				url: poster,
+				width: el.videoWidth || 0,
+				height: el.videoHeight || 0,
+				alt: el.getAttribute('aria-label') || '',
+				variants: Array.from(new Set(sources)).map(url => ({url})),
+			};
+		});
+	}
+	function readTweet(root, skip) {
+		const all = sel => scoped(root, skip, sel);
+		const user = all('[data-testid="User-Name"]')[0];
+		const handle = user ? (Array.from(user.querySelectorAll('span'))
+			.map(s => (s.textContent || '').trim()).find(t => t.startsWith('@')) || '') : '';
+		const nick = (user && user.querySelector('span span')) || all('div[dir="ltr"] span span')[0];
+		const pfp = all('img[src*="profile_images"]')[0] || all('img[alt][src*="pbs.twimg.com"]')[0];
+		const body = all('div[data-testid="tweetText"]')[0];
+		const time = all('time[datetime]')[0];
+		return {
+			username: all('a').map(a => (a.textContent || '').trim()).find(t => t.includes('@')) || handle,
+			user_nickname: nick ? nick.textContent : '',
+			user_profile_img: pfp ? pfp.src : '',
+			text: body ? body.innerText : '',
+			images: all('img[src*="pbs.twimg.com/media"]').map(i => i.src),
+			media: readMedia(all),
+			links: tweetLinks(all('a[href]')),
+			posted_at: time ? time.getAttribute('datetime') : '',
+		};
+	}
+	const article = document.querySelector('article');
+	const quote = article && quoteCard(article);
+	const own = sel => scoped(article, quote, sel);
+`
+
+// tweetJS : tweetLibJS를 쓰는 스크립트 본문을 즉시 실행 함수로 감싼다.
+func tweetJS(body string) string {
+	return "(function(){" + tweetLibJS + body + "})()"
+}
+
+// quotedJS, retweetedByJS : 인용한 트윗(JSON, 없으면 빈 문자열)과 재게시한 사람
+var (
+	quotedJS      = tweetJS(`return quote ? JSON.stringify(readTweet(quote, null)) : '';`)
+	retweetedByJS = tweetJS(`return article ? retweetedBy(article) : '';`)
+)
+
+// notInQuoteXPath : 인용 카드 밖의 요소만 고르는 XPath 조건 (selenium)
+const notInQuoteXPath = `[not(ancestor::div[@role="link"][.//*[@data-testid="User-Name"]])]`
+
+// decodeQuoted : quotedJS 결과. 인용한 트윗이 없으면 nil
+func decodeQuoted(raw string) *TweetData {
+	if raw == "" {
+		return nil
+	}
+	var quoted TweetData
+	if err := json.Unmarshal([]byte(raw), &quoted); err != nil {
+		return nil
+	}
+	quoted.Text = strings.ReplaceAll(quoted.Text, "\n", " ")
+	quoted.PostedAt = normalizePostedAt(quoted.PostedAt)
+	quoted.Media = attachVariants(quoted.Media, nil)
+	return &quoted
+}
+
+// seleniumTraffic : 페이지가 지금까지 받아 온 동영상 스트림 주소 (videoResourcesJS)
+func seleniumTraffic(wd selenium.WebDriver) []string {
+	var traffic []string
+	if v, err := wd.ExecuteScript("return "+videoResourcesJS, nil); err == nil {
+		raw, _ := v.(string)
+		_ = json.Unmarshal([]byte(raw), &traffic)
+	}
+	return traffic
+}
+
+// tweetStateError : tweetStateJS 결과를 에러로 바꾼다. 정상이면 nil
+func tweetStateError(state string) error {
+	switch state {
+	case "article":
+		return nil
+	case "notfound":
+		return NewError(CodeNotFound, "tweet not found or deleted")
+	case "login", "blocked":
+		return NewError(CodeBlocked, "tweet is behind a login wall or blocked (%s)", state)
+	}
+	return NewError(CodeTimeout, "timed out waiting for <article>")
+}
+
+// ScrapeTweet : selenium으로 트윗을 긁는다. wait 동안 <article>이 뜨기를 기다린다.
+func ScrapeTweet(ctx context.Context, wd selenium.WebDriver, url string, wait time.Duration, opts TweetOptions) (*TweetData, error) {
+	slog.InfoContext(ctx, "📥 Scraping tweet", "url", url)
+	_, span := StartSpan(ctx, "selenium.navigate")
+	err := wd.Get(url)
+	EndSpan(span, err)
+	if err != nil {
 		return nil, fmt.Errorf("failed to load URL: %w", err)
 	}
 
-	// 대기
-	for i := 0; i < 20; i++ {
-		if _, err := wd.FindElement(selenium.ByCSSSelector, "article"); err == nil {
+	// <article> 또는 삭제/로그인 화면이 뜰 때까지 대기
+	_, span = StartSpan(ctx, "selenium.wait_state")
+	var state string
+	for end := time.Now().Add(wait); time.Now().Before(end); {
+		if v, err := wd.ExecuteScript("return "+tweetStateJS, nil); err == nil {
+			state, _ = v.(string)
+		}
+		if state != "" {
 			break
 		}
 		time.Sleep(500 * time.Millisecond)
 	}
-	if _, err := wd.FindElement(selenium.ByCSSSelector, "article"); err != nil {
+	err = tweetStateError(state)
+	EndSpan(span, err)
+	if err != nil {
 		src, _ := wd.PageSource()
 		_ = os.WriteFile("page.html", []byte(src), 0644)
-		return nil, fmt.Errorf("failed to find <article>: %w", err)
+		return nil, err
 	}
 
-	username := FindTextByXPath(wd, `//article//a[starts-with(@href, "/") and contains(., "@")]`)
-	nickname := FindTextByXPath(wd, `//article//div[@dir="ltr"]//span/span`)
-	profileImg := FindAttrByXPath(wd, `//article//img[contains(@src, 'profile_images')]`, "src")
-	metaTag := FindAttrByXPath(wd, `//meta[@property='og:title']`, "content")
-	text := FindTextByXPath(wd, `//article//div[@data-testid="tweetText"]`)
+	username := FindTextByXPath(ctx, wd, `//article//a[starts-with(@href, "/") and contains(., "@")]`+notInQuoteXPath)
+	nickname := FindTextByXPath(ctx, wd, `//article//div[@dir="ltr"]//span/span`+notInQuoteXPath)
+	profileImg := FindAttrByXPath(ctx, wd, `//article//img[contains(@src, 'profile_images')]`+notInQuoteXPath, "src")
+	metaTag := FindAttrByXPath(ctx, wd, `//meta[@property='og:title']`, "content")
+	text := FindTextByXPath(ctx, wd, `//article//div[@data-testid="tweetText"]`+notInQuoteXPath)
+	postedAt := FindAttrByXPath(ctx, wd, `//article//time[@datetime]`+notInQuoteXPath, "datetime")
+	debugField(ctx, "👤 Username", "username", username)
+	debugField(ctx, "📝 Text", "text", text)
 
-	// 이미지
+	// 이미지 (사진만. 동영상/GIF는 아래 Media)
 	var images []string
-	imgElements, _ := wd.FindElements(selenium.ByXPATH, `//img[contains(@src, 'https://pbs.twimg.com/media')]`)
+	imgElements, _ := findElements(ctx, wd, selenium.ByXPATH, `(//article)[1]//img[contains(@src, 'https://pbs.twimg.com/media')]`+notInQuoteXPath)
 	for _, img := range imgElements {
 		src, _ := img.GetAttribute("src")
 		images = append(images, src)
@@ -56,7 +246,7 @@ func ScrapeTweet(wd selenium.WebDriver, url string) (*TweetData, error) {
 
 	// 링크
 	var links []string
-	if linkElems, err := wd.FindElements(selenium.ByXPATH, `//article//a`); err == nil {
+	if linkElems, err := findElements(ctx, wd, selenium.ByXPATH, `(//article)[1]//a`+notInQuoteXPath); err == nil {
 		re := regexp.MustCompile(`[a-zA-Z0-9/-]*\.[a-zA-Z0-9/-]+[a-zA-Z0-9./-]*`)
 		for _, el := range linkElems {
 			linkText, _ := el.Text()
@@ -66,7 +256,7 @@ func ScrapeTweet(wd selenium.WebDriver, url string) (*TweetData, error) {
 		}
 	}
 
-	return &TweetData{
+	data := &TweetData{
 		Text:           strings.ReplaceAll(text, "\n", " "),
 		Images:         images,
 		Username:       username,
@@ -74,5 +264,51 @@ func ScrapeTweet(wd selenium.WebDriver, url string) (*TweetData, error) {
 		UserProfileImg: profileImg,
 		MetaTag:        strings.ReplaceAll(metaTag, "\n", " "),
 		Links:          links,
-	}, nil
+		PostedAt:       normalizePostedAt(postedAt),
+	}
+	currentURL, _ := wd.CurrentURL()
+	data.setStatusIdentity(currentURL)
+
+	// 사진/동영상/GIF. 동영상 스트림 주소는 페이지가 받아 온 리소스 목록에서 찾는다
+	traffic := seleniumTraffic(wd)
+	if v, err := wd.ExecuteScript("return "+mediaJS, nil); err == nil {
+		raw, _ := v.(string)
+		data.Media = decodeMedia(raw, traffic)
+	}
+
+	// 반응 수
+	if v, err := wd.ExecuteScript("return "+statsJS, nil); err == nil {
+		raw, _ := v.(string)
+		data.Stats = parseStats(raw)
+	}
+
+	// 인용한 트윗, 재게시
+	if v, err := wd.ExecuteScript("return "+quotedJS, nil); err == nil {
+		raw, _ := v.(string)
+		if data.Quoted = decodeQuoted(raw); data.Quoted != nil {
+			data.Quoted.Media = attachVariants(data.Quoted.Media, traffic)
+		}
+	}
+	if v, err := wd.ExecuteScript("return "+retweetedByJS, nil); err == nil {
+		data.RetweetedBy, _ = v.(string)
+	}
+
+	if opts.Thread > 0 {
+		thread, err := collectThread(ctx, currentURL, opts.Thread, func() ([]threadItem, error) {
+			v, err := wd.ExecuteScript("return "+threadItemsJS, nil)
+			if err != nil {
+				return nil, err
+			}
+			raw, _ := v.(string)
+			return decodeThreadItems(raw)
+		}, func() error {
+			_, err := wd.ExecuteScript(threadScrollJS, nil)
+			return err
+		}, func() []string { return seleniumTraffic(wd) })
+		if err != nil {
+			slog.WarnContext(ctx, "⚠️ Thread stopped early", "replies", len(thread), "err", err)
+		}
+		data.Thread, data.ThreadCut = thread, err != nil
+	}
+	return data, nil
 }
@@ -3,10 +3,9 @@ package internal
 import (
 	"context"
 	"encoding/json"
-	"errors"
+	"log/slog"
 	"os"
 	"strings"
-	"time"
 
 	"github.com/chromedp/cdproto/emulation"
 	"github.com/chromedp/cdproto/network"
@@ -14,18 +13,43 @@ import (
 )
 
 // ScrapeTweetChromedp는 chromedp로 공개 트윗 페이지에서 기본 정보를 긁어온다.
-// parent는 재사용 컨텍스트(반복 크롤링용)를 권장한다.
-func ScrapeTweetChromedp(parent context.Context, tweetURL string) (*TweetData, error) {
+// ctx는 탭 풀에서 빌린 탭 컨텍스트에 제한 시간을 걸어서 넘긴다.
+func ScrapeTweetChromedp(ctx context.Context, tweetURL string, opts TweetOptions) (*TweetData, error) {
 	if tweetURL == "" {
-		return nil, errors.New("empty url")
+		return nil, NewError(CodeInvalidURL, "empty url")
 	}
 	if !strings.HasPrefix(tweetURL, "http") {
 		tweetURL = "https://" + tweetURL
 	}
 
-	// 각 호출마다 타임아웃을 별도로 건다.
-	ctx, cancel := context.WithTimeout(parent, 25*time.Second)
-	defer cancel()
+	// 동영상 스트림 주소는 페이지가 요청하는 video.twimg.com 주소에서 모은다
+	traffic := &videoTraffic{}
+	listenCtx, stopListen := context.WithCancel(ctx)
+	defer stopListen()
+	chromedp.ListenTarget(listenCtx, func(ev any) {
+		if ev, ok := ev.(*network.EventRequestWillBeSent); ok && ev.Request != nil {
+			traffic.add(ev.Request.URL)
+		}
+	})
+
+	// <article> 또는 삭제/로그인 화면이 뜰 때까지 대기
+	var state string
+	err := chromedp.Run(ctx,
+		network.Enable(),
+		emulation.SetLocaleOverride(),
+		tracedAction("chromedp.navigate",
+			chromedp.Navigate(tweetURL),
+			chromedp.WaitReady("body", chromedp.ByQuery),
+		),
+		tracedAction("chromedp.wait_state", chromedp.Poll(tweetStateJS, &state)),
+	)
+	if err != nil {
+		saveErrorScreenshot(ctx)
+		return nil, err
+	}
+	if err := tweetStateError(state); err != nil {
+		return nil, err
+	}
 
 	// 결과 변수
 	var (
@@ -33,82 +57,66 @@ func ScrapeTweetChromedp(parent context.Context, tweetURL string) (*TweetData, e
 		username, nickname, pfp, txt string
 		ogTitle                      string
 		imagesJSON, linksJSON        string
+		mediaJSON                    string
+		quotedJSON, retweetedBy      string
+		postedAt, statsJSON          string
 	)
 
-	ua := "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 " +
-		"(KHTML, like Gecko) Chrome/121.0.0.0 Safari/537.36"
-
 	tasks := chromedp.Tasks{
-		network.Enable(),
-		emulation.SetUserAgentOverride(ua),
-		emulation.SetLocaleOverride(),
-
-		chromedp.Navigate(tweetURL),
-
-		// 핵심 노드가 붙을 때까지 대기
-		chromedp.WaitReady("body", chromedp.ByQuery),
-		chromedp.WaitVisible("article", chromedp.ByQuery),
+		tracedAction("chromedp.wait_article", chromedp.WaitVisible("article", chromedp.ByQuery)),
 
 		chromedp.Title(&title),
 		chromedp.Location(&currentURL),
 
 		// @username
-		chromedp.EvaluateAsDevTools(`(function(){
-			const atexts = Array.from(document.querySelectorAll('article a'))
-				.map(a => (a.textContent||'').trim());
-			return atexts.find(t => t.includes('@')) || '';
-		})()`, &username),
+		tracedAction("extract.username", chromedp.EvaluateAsDevTools(tweetJS(`
+			return own('a').map(a => (a.textContent||'').trim()).find(t => t.includes('@')) || '';
+		`), &username)),
 
 		// 닉네임(표시명)
-		chromedp.EvaluateAsDevTools(`(function(){
-			const el = document.querySelector('article [data-testid="User-Name"] span span')
-				|| document.querySelector('article div[dir="ltr"] span span');
+		tracedAction("extract.nickname", chromedp.EvaluateAsDevTools(tweetJS(`
+			const el = own('[data-testid="User-Name"] span span')[0] || own('div[dir="ltr"] span span')[0];
 			return el ? el.textContent : '';
-		})()`, &nickname),
+		`), &nickname)),
 
 		// 프로필 이미지 (대체 선택자 포함)
-		chromedp.EvaluateAsDevTools(`(function(){
-			return (document.querySelector('article img[src*="profile_images"]')
-				|| document.querySelector('article img[alt][src*="pbs.twimg.com"]'))?.src || '';
-		})()`, &pfp),
+		tracedAction("extract.profile_img", chromedp.EvaluateAsDevTools(tweetJS(`
+			return (own('img[src*="profile_images"]')[0] || own('img[alt][src*="pbs.twimg.com"]')[0])?.src || '';
+		`), &pfp)),
 
 		// 본문 텍스트
-		chromedp.EvaluateAsDevTools(`(function(){
-			const el = document.querySelector('article div[data-testid="tweetText"]');
+		tracedAction("extract.text", chromedp.EvaluateAsDevTools(tweetJS(`
+			const el = own('div[data-testid="tweetText"]')[0];
 			return el ? el.innerText : '';
-		})()`, &txt),
+		`), &txt)),
 
 		// og:title (있으면 메타로 보완)
-		chromedp.AttributeValue(`meta[property="og:title"]`, "content", &ogTitle, nil),
-
-		// 이미지들
-		chromedp.EvaluateAsDevTools(`JSON.stringify(
-			Array.from(document.querySelectorAll('article img[src*="pbs.twimg.com/media"]'))
-				.map(i => i.src)
-		)`, &imagesJSON),
-
-		// 링크들: 절대/상대/href/text 안의 URL 모두 수집(Set으로 중복 제거)
-		chromedp.EvaluateAsDevTools(`(function(){
-			const out = new Set();
-			for (const a of document.querySelectorAll('article a[href]')) {
-				const href = a.getAttribute('href') || '';
-				if (href.startsWith('http')) out.add(href);
-				else if (href.startsWith('/')) out.add('https://x.com' + href);
-
-				const txt = (a.textContent||'').trim();
-				const m = txt.match(/[a-zA-Z]+:\/\/[^\s]+/);
-				if (m) out.add(m[0]);
-			}
-			return JSON.stringify(Array.from(out));
-		})()`, &linksJSON),
+		tracedAction("extract.meta_tag", chromedp.AttributeValue(`meta[property="og:title"]`, "content", &ogTitle, nil)),
+
+		// 이미지들 (인용 카드 제외)
+		tracedAction("extract.images", chromedp.EvaluateAsDevTools(tweetJS(`
+			return JSON.stringify(own('img[src*="pbs.twimg.com/media"]').map(i => i.src));
+		`), &imagesJSON)),
+
+		// 사진/동영상/GIF (인용 카드 제외)
+		tracedAction("extract.media", chromedp.EvaluateAsDevTools(mediaJS, &mediaJSON)),
+
+		// 링크들: 절대/상대/href/text 안의 URL 모두 수집(Set으로 중복 제거, 인용 카드 제외)
+		tracedAction("extract.links", chromedp.EvaluateAsDevTools(tweetJS(`
+			return JSON.stringify(tweetLinks(own('a[href]')));
+		`), &linksJSON)),
+
+		// 올린 시각, 반응 수
+		tracedAction("extract.posted_at", chromedp.EvaluateAsDevTools(postedAtJS, &postedAt)),
+		tracedAction("extract.stats", chromedp.EvaluateAsDevTools(statsJS, &statsJSON)),
+
+		// 인용한 트윗, 재게시
+		tracedAction("extract.quoted", chromedp.EvaluateAsDevTools(quotedJS, &quotedJSON)),
+		tracedAction("extract.retweeted_by", chromedp.EvaluateAsDevTools(retweetedByJS, &retweetedBy)),
 	}
 
 	if err := chromedp.Run(ctx, tasks); err != nil {
-		// 디버깅용 스크린샷 남기기 (선택)
-		var png []byte
-		if ssErr := chromedp.Run(ctx, chromedp.CaptureScreenshot(&png)); ssErr == nil && len(png) > 0 {
-			_ = os.WriteFile("tweet_error.png", png, 0644)
-		}
+		saveErrorScreenshot(ctx)
 		return nil, err
 	}
 
@@ -123,13 +131,47 @@ func ScrapeTweetChromedp(parent context.Context, tweetURL string) (*TweetData, e
 		metaTitle = title
 	}
 
-	return &TweetData{
+	urls := traffic.list()
+	data := &TweetData{
 		Text:           strings.ReplaceAll(txt, "\n", " "),
 		Images:         images,
+		Media:          decodeMedia(mediaJSON, urls),
 		Username:       username,
 		UserNickname:   nickname,
 		UserProfileImg: pfp,
 		MetaTag:        metaTitle,
 		Links:          links,
-	}, nil
+		PostedAt:       normalizePostedAt(postedAt),
+		Stats:          parseStats(statsJSON),
+		Quoted:         decodeQuoted(quotedJSON),
+		RetweetedBy:    retweetedBy,
+	}
+	if data.Quoted != nil {
+		data.Quoted.Media = attachVariants(data.Quoted.Media, urls)
+	}
+	data.setStatusIdentity(currentURL)
+	if opts.Thread > 0 {
+		thread, err := collectThread(ctx, currentURL, opts.Thread, func() ([]threadItem, error) {
+			var raw string
+			if err := chromedp.Run(ctx, chromedp.EvaluateAsDevTools(threadItemsJS, &raw)); err != nil {
+				return nil, err
+			}
+			return decodeThreadItems(raw)
+		}, func() error {
+			return chromedp.Run(ctx, chromedp.Evaluate(threadScrollJS, nil))
+		}, traffic.list)
+		if err != nil {
+			slog.WarnContext(ctx, "⚠️ Thread stopped early", "replies", len(thread), "err", err)
+		}
+		data.Thread, data.ThreadCut = thread, err != nil
+	}
+	return data, nil
+}
+
+// saveErrorScreenshot : 디버깅용 스크린샷 남기기 (선택)
+func saveErrorScreenshot(ctx context.Context) {
+	var png []byte
+	if err := chromedp.Run(ctx, chromedp.CaptureScreenshot(&png)); err == nil && len(png) > 0 {
+		_ = os.WriteFile("tweet_error.png", png, 0644)
+	}
 }
@@ -0,0 +1,157 @@
+package internal
+
+import (
+	"encoding/json"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// MediaItem : 트윗에 붙은 사진/동영상/GIF 하나
+type MediaItem struct {
+	Type     string         `json:"type"`               // photo | video | animated_gif
+	URL      string         `json:"url"`                // 사진 주소. 동영상/GIF는 포스터(썸네일) 주소
+	Width    int            `json:"width,omitempty"`    // 알 수 있을 때만
+	Height   int            `json:"height,omitempty"`   // 알 수 있을 때만
+	Alt      string         `json:"alt,omitempty"`      // 대체 텍스트
+	Variants []MediaVariant `json:"variants,omitempty"` // 동영상/GIF 스트림 주소들
+}
+
+// MediaVariant : 동영상 스트림 하나 (mp4 또는 HLS 재생 목록)
+type MediaVariant struct {
+	URL         string `json:"url"`
+	ContentType string `json:"content_type,omitempty"` // video/mp4 | application/x-mpegURL
+	Width       int    `json:"width,omitempty"`
+	Height      int    `json:"height,omitempty"`
+}
+
+// mediaJS : 바깥 트윗(인용 카드 제외)의 사진/동영상/GIF를 화면 순서대로 읽는다.
+var mediaJS = tweetJS(`return JSON.stringify(readMedia(own));`)
+
+// videoResourcesJS : 페이지가 지금까지 받아 온 video.twimg.com 주소 (selenium용).
+// 브라우저의 리소스 타이밍 버퍼가 차면 그 뒤의 요청은 빠질 수 있다.
+const videoResourcesJS = `JSON.stringify(performance.getEntriesByType('resource').map(e => e.name).filter(u => u.includes('video.twimg.com')))`
+
+// videoTraffic : chromedp 네트워크 이벤트에서 본 동영상 스트림 주소. 이벤트 처리 고루틴에서 쓴다.
+type videoTraffic struct {
+	mu   sync.Mutex
+	urls []string
+	seen map[string]bool
+}
+
+func (v *videoTraffic) add(rawURL string) {
+	if _, ok := variantOf(rawURL); !ok {
+		return
+	}
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	if v.seen == nil {
+		v.seen = map[string]bool{}
+	}
+	if !v.seen[rawURL] {
+		v.seen[rawURL] = true
+		v.urls = append(v.urls, rawURL)
+	}
+}
+
+func (v *videoTraffic) list() []string {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	return append([]string(nil), v.urls...)
+}
+
+// mediaKeyRe : 포스터와 스트림 주소에 같이 들어가는 미디어 키.
+// 예) ext_tw_video_thumb/123/pu/img/a.jpg 와 ext_tw_video/123/pu/vid/avc1/720x1280/b.mp4 는 같은 동영상,
+// tweet_video_thumb/GmqAbC.jpg 와 tweet_video/GmqAbC.mp4 는 같은 GIF
+var mediaKeyRe = regexp.MustCompile(`/(ext_tw_video|amplify_video|tweet_video)(?:_thumb)?/([A-Za-z0-9_-]+)`)
+
+// resolutionRe : 스트림 주소 경로의 해상도 (…/720x1280/…)
+var resolutionRe = regexp.MustCompile(`/(\d+)x(\d+)/`)
+
+// mediaKey : 포스터나 스트림 주소의 미디어 키. 모르는 주소면 빈 문자열
+func mediaKey(rawURL string) string {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return ""
+	}
+	m := mediaKeyRe.FindStringSubmatch(u.Path)
+	if m == nil {
+		return ""
+	}
+	return m[1] + "/" + m[2]
+}
+
+// variantOf : video.twimg.com 의 mp4/m3u8 주소를 스트림 하나로 읽는다. 조각(.m4s, .ts)이나 다른 주소는 false
+func variantOf(rawURL string) (MediaVariant, bool) {
+	u, err := url.Parse(rawURL)
+	if err != nil || u.Hostname() != "video.twimg.com" {
+		return MediaVariant{}, false
+	}
+	v := MediaVariant{URL: rawURL}
+	switch {
+	case strings.HasSuffix(u.Path, ".mp4"):
+		v.ContentType = "video/mp4"
+	case strings.HasSuffix(u.Path, ".m3u8"):
+		v.ContentType = "application/x-mpegURL"
+	default:
+		return MediaVariant{}, false
+	}
+	if m := resolutionRe.FindStringSubmatch(u.Path); m != nil {
+		v.Width, _ = strconv.Atoi(m[1])
+		v.Height, _ = strconv.Atoi(m[2])
+	}
+	return v, true
+}
+
+// attachVariants : 화면에서 읽은 동영상/GIF에 네트워크에서 본 스트림 주소를 미디어 키로 붙이고,
+// 스트림 종류와 해상도를 채운다. 플레이어 크기를 모르면 가장 큰 스트림 해상도를 쓴다.
+func attachVariants(media []MediaItem, traffic []string) []MediaItem {
+	for i := range media {
+		item := &media[i]
+		if item.Type == "photo" {
+			continue
+		}
+		key := mediaKey(item.URL)
+		urls := make([]string, 0, len(item.Variants))
+		for _, v := range item.Variants {
+			urls = append(urls, v.URL)
+			if key == "" {
+				key = mediaKey(v.URL)
+			}
+		}
+		if key != "" {
+			for _, u := range traffic {
+				if mediaKey(u) == key {
+					urls = append(urls, u)
+				}
+			}
+		}
+
+		sized := item.Width > 0 && item.Height > 0
+		seen := map[string]bool{}
+		item.Variants = nil
+		for _, u := range urls {
+			v, ok := variantOf(u)
+			if !ok || seen[u] {
+				continue
+			}
+			seen[u] = true
+			item.Variants = append(item.Variants, v)
+			if !sized && v.Width*v.Height > item.Width*item.Height {
+				item.Width, item.Height = v.Width, v.Height
+			}
+		}
+	}
+	return media
+}
+
+// decodeMedia : mediaJS 결과(JSON 문자열)에 스트림 주소를 붙인다.
+func decodeMedia(raw string, traffic []string) []MediaItem {
+	var media []MediaItem
+	if err := json.Unmarshal([]byte(raw), &media); err != nil {
+		return nil
+	}
+	return attachVariants(media, traffic)
+}
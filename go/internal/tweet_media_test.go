@@ -0,0 +1,86 @@
+package internal
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestVariantOf(t *testing.T) {
+	cases := []struct {
+		in   string
+		want MediaVariant
+		ok   bool
+	}{
+		{"https://video.twimg.com/ext_tw_video/123/pu/vid/avc1/720x1280/a.mp4?tag=12",
+			MediaVariant{URL: "https://video.twimg.com/ext_tw_video/123/pu/vid/avc1/720x1280/a.mp4?tag=12", ContentType: "video/mp4", Width: 720, Height: 1280}, true},
+		{"https://video.twimg.com/ext_tw_video/123/pu/pl/b.m3u8?tag=12",
+			MediaVariant{URL: "https://video.twimg.com/ext_tw_video/123/pu/pl/b.m3u8?tag=12", ContentType: "application/x-mpegURL"}, true},
+		{"https://video.twimg.com/ext_tw_video/123/pu/vid/avc1/0/3000/720x1280/c.m4s", MediaVariant{}, false},
+		{"https://pbs.twimg.com/media/GmqMNf0bYAAUnTD.mp4", MediaVariant{}, false},
+	}
+	for _, c := range cases {
+		got, ok := variantOf(c.in)
+		if got != c.want || ok != c.ok {
+			t.Errorf("variantOf(%q) = %+v, %v", c.in, got, ok)
+		}
+	}
+}
+
+func TestDecodeMedia(t *testing.T) {
+	raw := `[
+		{"type":"photo","url":"https://pbs.twimg.com/media/A.jpg?name=small","width":680,"height":383,"alt":"흑백 샘플"},
+		{"type":"video","url":"https://pbs.twimg.com/ext_tw_video_thumb/123/pu/img/p.jpg","width":0,"height":0,"alt":"","variants":[]},
+		{"type":"animated_gif","url":"https://pbs.twimg.com/tweet_video_thumb/GmqAbC.jpg","width":480,"height":270,
+			"variants":[{"url":"https://video.twimg.com/tweet_video/GmqAbC.mp4"}]}
+	]`
+	traffic := []string{
+		"https://video.twimg.com/ext_tw_video/123/pu/pl/master.m3u8?tag=12",
+		"https://video.twimg.com/ext_tw_video/123/pu/vid/avc1/320x568/s.mp4",
+		"https://video.twimg.com/ext_tw_video/123/pu/vid/avc1/720x1280/l.mp4",
+		"https://video.twimg.com/ext_tw_video/999/pu/vid/avc1/720x1280/other.mp4", // 다른 트윗의 동영상
+		"https://video.twimg.com/tweet_video/GmqAbC.mp4",
+	}
+	media := decodeMedia(raw, traffic)
+	if len(media) != 3 {
+		t.Fatalf("media = %+v", media)
+	}
+	if p := media[0]; p.Type != "photo" || p.Alt != "흑백 샘플" || p.Width != 680 || p.Height != 383 || p.Variants != nil {
+		t.Errorf("photo = %+v", p)
+	}
+	// 플레이어 크기를 모르면 가장 큰 스트림 해상도
+	if v := media[1]; len(v.Variants) != 3 || v.Width != 720 || v.Height != 1280 || v.Variants[0].ContentType != "application/x-mpegURL" {
+		t.Errorf("video = %+v", v)
+	}
+	// 화면의 주소와 네트워크에서 본 주소가 같으면 한 번만
+	if g := media[2]; len(g.Variants) != 1 || g.Width != 480 || g.Variants[0].ContentType != "video/mp4" {
+		t.Errorf("gif = %+v", g)
+	}
+
+	if media := decodeMedia("", traffic); media != nil {
+		t.Errorf("no result: got %+v", media)
+	}
+}
+
+func TestVideoTraffic(t *testing.T) {
+	var traffic videoTraffic
+	traffic.add("https://video.twimg.com/tweet_video/GmqAbC.mp4")
+	traffic.add("https://video.twimg.com/tweet_video/GmqAbC.mp4")
+	traffic.add("https://abs.twimg.com/responsive-web/client-web/main.js")
+	if urls := traffic.list(); len(urls) != 1 {
+		t.Errorf("urls = %v", urls)
+	}
+}
+
+func TestMediaKeepsImages(t *testing.T) {
+	// 동영상만 있는 트윗도 완전한 결과이고, images는 예전처럼 배열로 남는다
+	data := TweetData{Images: []string{}, Media: []MediaItem{{Type: "video", URL: "https://pbs.twimg.com/ext_tw_video_thumb/1/pu/img/p.jpg"}}}
+	if !data.complete() {
+		t.Error("video-only tweet should be complete")
+	}
+	raw, _ := json.Marshal(data)
+	var got map[string]any
+	json.Unmarshal(raw, &got)
+	if _, ok := got["images"].([]any); !ok || got["media"] == nil {
+		t.Errorf("json = %s", raw)
+	}
+}
@@ -0,0 +1,164 @@
+package internal
+
+import (
+	"encoding/json"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// TweetStats : 답글/재게시/마음에 들어요/북마크/조회수
+type TweetStats struct {
+	Replies   int `json:"replies"`
+	Reposts   int `json:"reposts"`
+	Likes     int `json:"likes"`
+	Bookmarks int `json:"bookmarks"`
+	Views     int `json:"views"`
+}
+
+// statsJS : 반응 버튼들의 aria-label(없으면 화면 글자)과 버튼 묶음 전체의 aria-label을 읽는다.
+// 버튼 글자는 "1.2만"처럼 줄여 쓰고, aria-label에는 보통 전체 숫자가 있다.
+var statsJS = tweetJS(`
+	const label = sel => {
+		const el = own(sel)[0];
+		return el ? (el.getAttribute('aria-label') || el.textContent || '') : '';
+	};
+	const group = own('[role="group"][aria-label]')[0];
+	return JSON.stringify({
+		replies: label('[data-testid="reply"]'),
+		reposts: label('[data-testid="retweet"], [data-testid="unretweet"]'),
+		likes: label('[data-testid="like"], [data-testid="unlike"]'),
+		bookmarks: label('[data-testid="bookmark"], [data-testid="removeBookmark"]'),
+		views: label('a[href$="/analytics"]'),
+		group: group ? group.getAttribute('aria-label') : '',
+	});
+`)
+
+// postedAtJS : 바깥 트윗(인용 카드 제외)의 <time datetime>
+var postedAtJS = tweetJS(`
+	const el = own('time[datetime]')[0];
+	return el ? el.getAttribute('datetime') : '';
+`)
+
+// statsLabels : statsJS 결과
+type statsLabels struct {
+	Replies   string `json:"replies"`
+	Reposts   string `json:"reposts"`
+	Likes     string `json:"likes"`
+	Bookmarks string `json:"bookmarks"`
+	Views     string `json:"views"`
+	Group     string `json:"group"`
+}
+
+// countRe : 숫자와 단위. 1,234 / 1.2K / 3M / 1.2만 / 3천 / 1억
+// 영어 단위는 대문자 한 글자만 본다 ("12 bookmarks"의 b는 단위가 아니다).
+var countRe = regexp.MustCompile(`(\d+(?:[.,]\d+)*)\s*([KMB]\b|천|만|억)?`)
+
+var countUnits = map[string]float64{
+	"K": 1e3, "M": 1e6, "B": 1e9,
+	"천": 1e3, "만": 1e4, "억": 1e8,
+}
+
+// parseCount : 글자에서 첫 숫자를 읽는다. 숫자가 없으면 false
+func parseCount(s string) (int, bool) {
+	m := countRe.FindStringSubmatch(s)
+	if m == nil {
+		return 0, false
+	}
+	f, err := strconv.ParseFloat(strings.ReplaceAll(m[1], ",", ""), 64)
+	if err != nil {
+		return 0, false
+	}
+	if unit, ok := countUnits[m[2]]; ok {
+		f *= unit
+	}
+	return int(math.Round(f)), true
+}
+
+// statKeywords : 버튼 묶음 aria-label 에서 항목을 알아보는 단어 ("답글 5개, 재게시 2회, ...")
+var statKeywords = []struct {
+	words []string
+	field func(*TweetStats) *int
+}{
+	{[]string{"repl", "답글"}, func(s *TweetStats) *int { return &s.Replies }},
+	{[]string{"repost", "retweet", "재게시", "리트윗", "리포스트"}, func(s *TweetStats) *int { return &s.Reposts }},
+	{[]string{"like", "마음에"}, func(s *TweetStats) *int { return &s.Likes }},
+	{[]string{"bookmark", "북마크"}, func(s *TweetStats) *int { return &s.Bookmarks }},
+	{[]string{"view", "조회"}, func(s *TweetStats) *int { return &s.Views }},
+}
+
+// parseStats : statsJS 결과를 숫자로 바꾼다. 읽은 것이 하나도 없으면 nil
+func parseStats(raw string) *TweetStats {
+	var labels statsLabels
+	if err := json.Unmarshal([]byte(raw), &labels); err != nil {
+		return nil
+	}
+	var (
+		stats TweetStats
+		found bool
+	)
+	// 묶음 라벨에 전체 숫자가 있으니 먼저 읽고, 빠진 항목은 버튼에서 채운다
+	for _, part := range strings.Split(labels.Group, ", ") {
+		lower := strings.ToLower(part)
+		for _, k := range statKeywords {
+			if !containsAny(lower, k.words) {
+				continue
+			}
+			if n, ok := parseCount(part); ok {
+				*k.field(&stats), found = n, true
+			}
+			break
+		}
+	}
+	buttons := []struct {
+		label string
+		field *int
+	}{
+		{labels.Replies, &stats.Replies},
+		{labels.Reposts, &stats.Reposts},
+		{labels.Likes, &stats.Likes},
+		{labels.Bookmarks, &stats.Bookmarks},
+		{labels.Views, &stats.Views},
+	}
+	for _, b := range buttons {
+		if *b.field != 0 {
+			continue
+		}
+		if n, ok := parseCount(b.label); ok {
+			*b.field, found = n, true
+		} else if b.label != "" {
+			found = true
+		}
+	}
+	if !found {
+		return nil
+	}
+	return &stats
+}
+
+func containsAny(s string, words []string) bool {
+	for _, w := range words {
+		if strings.Contains(s, w) {
+			return true
+		}
+	}
+	return false
+}
+
+// normalizePostedAt : <time datetime> 값을 RFC3339(UTC)로 맞춘다. 읽을 수 없으면 빈 문자열
+func normalizePostedAt(datetime string) string {
+	t, err := time.Parse(time.RFC3339Nano, strings.TrimSpace(datetime))
+	if err != nil {
+		return ""
+	}
+	return t.UTC().Format(time.RFC3339)
+}
+
+// setStatusIdentity : 최종 주소(리다이렉트 후)에서 트윗 ID와 작성자 핸들을 채운다.
+func (t *TweetData) setStatusIdentity(finalURL string) {
+	if handle, id, ok := parseStatusURL(finalURL); ok {
+		t.Handle, t.ID = handle, id
+	}
+}
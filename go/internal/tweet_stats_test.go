@@ -0,0 +1,81 @@
+package internal
+
+import "testing"
+
+func TestParseCount(t *testing.T) {
+	cases := []struct {
+		in   string
+		want int
+		ok   bool
+	}{
+		{"1.2만", 12000, true},
+		{"조회수 3.4만회", 34000, true},
+		{"3천", 3000, true},
+		{"1억", 100000000, true},
+		{"1,234", 1234, true},
+		{"12.5K", 12500, true},
+		{"2M", 2000000, true},
+		{"12 bookmarks", 12, true},
+		{"Reply", 0, false},
+		{"", 0, false},
+	}
+	for _, c := range cases {
+		got, ok := parseCount(c.in)
+		if got != c.want || ok != c.ok {
+			t.Errorf("parseCount(%q) = %d, %v; want %d, %v", c.in, got, ok, c.want, c.ok)
+		}
+	}
+}
+
+func TestParseStats(t *testing.T) {
+	// 묶음 라벨의 전체 숫자가 버튼의 줄인 숫자보다 우선한다
+	stats := parseStats(`{"replies":"5","likes":"1.2만","views":"","group":"답글 5개, 재게시 12회, 마음에 들어요 12,345개, 북마크 3개, 조회수 1,234,567회"}`)
+	want := TweetStats{Replies: 5, Reposts: 12, Likes: 12345, Bookmarks: 3, Views: 1234567}
+	if stats == nil || *stats != want {
+		t.Errorf("korean group = %+v", stats)
+	}
+
+	// 묶음 라벨이 없으면 버튼에서 읽는다
+	stats = parseStats(`{"replies":"12 Replies. Reply","reposts":"3.4K reposts. Repost","likes":"1.2만","bookmarks":"Bookmark","views":"2M views. View post analytics","group":""}`)
+	want = TweetStats{Replies: 12, Reposts: 3400, Likes: 12000, Views: 2000000}
+	if stats == nil || *stats != want {
+		t.Errorf("buttons = %+v", stats)
+	}
+
+	if stats := parseStats(`{"group":""}`); stats != nil {
+		t.Errorf("no buttons: got %+v", stats)
+	}
+	if stats := parseStats(""); stats != nil {
+		t.Errorf("no result: got %+v", stats)
+	}
+}
+
+func TestNormalizePostedAt(t *testing.T) {
+	if got := normalizePostedAt("2024-03-01T12:34:56.000Z"); got != "2024-03-01T12:34:56Z" {
+		t.Errorf("got %q", got)
+	}
+	if got := normalizePostedAt("2024-03-01T21:34:56+09:00"); got != "2024-03-01T12:34:56Z" {
+		t.Errorf("offset: got %q", got)
+	}
+	if got := normalizePostedAt("어제"); got != "" {
+		t.Errorf("invalid: got %q", got)
+	}
+}
+
+func TestSetStatusIdentity(t *testing.T) {
+	var data TweetData
+	data.setStatusIdentity("https://x.com/Einys/status/1234567890?s=20")
+	if data.Handle != "Einys" || data.ID != "1234567890" {
+		t.Errorf("data = %+v", data)
+	}
+	// 트윗 주소가 아니면 그대로 둔다
+	data.setStatusIdentity("https://x.com/i/flow/login")
+	if data.Handle != "Einys" || data.ID != "1234567890" {
+		t.Errorf("login redirect changed identity: %+v", data)
+	}
+
+	reply := threadItem{ID: "42", Handle: "einys", Tweet: TweetData{PostedAt: "2024-03-01T12:00:00.000Z"}}.tweet(nil)
+	if reply.ID != "42" || reply.Handle != "einys" || reply.PostedAt != "2024-03-01T12:00:00Z" {
+		t.Errorf("reply = %+v", reply)
+	}
+}
@@ -0,0 +1,51 @@
+package internal
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+)
+
+func TestDecodeQuoted(t *testing.T) {
+	if q := decodeQuoted(""); q != nil {
+		t.Errorf("no quote card: got %+v", q)
+	}
+	q := decodeQuoted(`{"username":"@other","text":"line1\nline2","images":["https://pbs.twimg.com/media/q.jpg"]}`)
+	if q == nil || q.Username != "@other" || q.Text != "line1 line2" || len(q.Images) != 1 {
+		t.Errorf("quoted = %+v", q)
+	}
+
+	// 타래 답글도 인용한 트윗을 따로 둔다
+	reply := threadItem{Tweet: TweetData{Text: "reply"}, Quoted: &TweetData{Text: "q\nq"}, RetweetedBy: "@fan"}.tweet(nil)
+	if reply.Quoted == nil || reply.Quoted.Text != "q q" || reply.RetweetedBy != "@fan" {
+		t.Errorf("reply = %+v", reply)
+	}
+}
+
+func TestQuotedJSON(t *testing.T) {
+	data := TweetData{
+		Text:        "outer",
+		Images:      []string{"https://pbs.twimg.com/media/outer.jpg"},
+		Quoted:      &TweetData{Text: "inner", Images: []string{"https://pbs.twimg.com/media/inner.jpg"}},
+		RetweetedBy: "@fan",
+		CacheInfo:   CacheInfo{FetchedAt: time.Now()},
+	}
+	raw, _ := json.Marshal(data)
+	var got map[string]any
+	json.Unmarshal(raw, &got)
+	quoted, _ := got["quoted"].(map[string]any)
+	if quoted["text"] != "inner" || got["retweeted_by"] != "@fan" || len(got["images"].([]any)) != 1 {
+		t.Errorf("json = %s", raw)
+	}
+	// 인용한 트윗에는 가져온 시각이 없다
+	if _, ok := quoted["fetched_at"]; ok || got["fetched_at"] == nil {
+		t.Errorf("fetched_at: json = %s", raw)
+	}
+
+	raw, _ = json.Marshal(TweetData{Text: "plain"})
+	var plain map[string]any
+	json.Unmarshal(raw, &plain)
+	if _, ok := plain["quoted"]; ok {
+		t.Errorf("quoted should be omitted: %s", raw)
+	}
+}
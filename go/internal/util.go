@@ -1,35 +1,50 @@
 package internal
 
 import (
-	"errors"
-	"log"
+	"context"
+	"fmt"
+	"log/slog"
+	"net/url"
+	"strings"
 	"time"
 
 	"github.com/tebeka/selenium"
 )
 
-func FindTextByXPath(wd selenium.WebDriver, xpath string) string {
-	elem, err := wd.FindElement(selenium.ByXPATH, xpath)
+func FindTextByXPath(ctx context.Context, wd selenium.WebDriver, xpath string) string {
+	elem, err := findElement(ctx, wd, selenium.ByXPATH, xpath)
 	if err != nil {
-		log.Printf("❌ Failed to find element: %v", err)
+		slog.WarnContext(ctx, "❌ Failed to find element", "xpath", xpath, "err", err)
 		return ""
 	}
 	text, _ := elem.Text()
 	return text
 }
 
-func FindAttrByXPath(wd selenium.WebDriver, xpath, attr string) string {
-	elem, err := wd.FindElement(selenium.ByXPATH, xpath)
+func FindAttrByXPath(ctx context.Context, wd selenium.WebDriver, xpath, attr string) string {
+	elem, err := findElement(ctx, wd, selenium.ByXPATH, xpath)
 	if err != nil {
-		log.Printf("❌ Failed to find element: %v", err)
+		slog.WarnContext(ctx, "❌ Failed to find element", "xpath", xpath, "err", err)
 		return ""
 	}
 	val, _ := elem.GetAttribute(attr)
 	return val
 }
 
-func WaitForPageLoad(wd selenium.WebDriver, timeoutSeconds int) error {
-	timeout := time.Duration(timeoutSeconds) * time.Second
+// navigate : 페이지를 열고 로딩이 끝날 때까지 기다린다 (selenium.navigate span).
+func navigate(ctx context.Context, wd selenium.WebDriver, pageURL string, wait time.Duration) error {
+	_, span := StartSpan(ctx, "selenium.navigate")
+	err := wd.Get(pageURL)
+	if err == nil {
+		if err = WaitForPageLoad(wd, wait); err != nil {
+			err = fmt.Errorf("failed to wait for page load: %w", err)
+		}
+	}
+	EndSpan(span, err)
+	return err
+}
+
+func WaitForPageLoad(wd selenium.WebDriver, timeout time.Duration) error {
 	end := time.Now().Add(timeout)
 	for {
 		state, err := wd.ExecuteScript("return document.readyState", nil)
@@ -37,8 +52,63 @@ func WaitForPageLoad(wd selenium.WebDriver, timeoutSeconds int) error {
 			return nil
 		}
 		if time.Now().After(end) {
-			return errors.New("timeout waiting for page load")
+			return NewError(CodeTimeout, "timeout waiting for page load")
 		}
 		time.Sleep(500 * time.Millisecond)
 	}
 }
+
+// MatchHost : host가 pattern 도메인(또는 그 하위 도메인)인지 확인한다.
+// pattern은 "x.com", "*.notion.site", ".notion.site" 형태를 허용한다.
+func MatchHost(host, pattern string) bool {
+	host = strings.ToLower(strings.TrimSuffix(host, "."))
+	pattern = strings.ToLower(strings.TrimLeft(pattern, "*."))
+	if pattern == "" {
+		return false
+	}
+	return host == pattern || strings.HasSuffix(host, "."+pattern)
+}
+
+// twitterHosts : 같은 트윗을 가리키는 호스트들. 정규화하면 x.com으로 바꾼다.
+var twitterHosts = map[string]bool{
+	"x.com": true, "www.x.com": true, "mobile.x.com": true,
+	"twitter.com": true, "www.twitter.com": true, "mobile.twitter.com": true,
+}
+
+// CanonicalURL : 같은 페이지를 가리키는 URL을 하나로 맞춘다 (캐시/중복 요청 키).
+// 스킴/호스트 소문자, 기본 포트와 #fragment, 끝 슬래시, utm_* 같은 추적 파라미터를 지우고 나머지 파라미터는 정렬한다.
+// 트윗은 호스트를 x.com으로 바꾸고 ?s=20 같은 공유 파라미터를 모두 지운다.
+func CanonicalURL(raw string) string {
+	u, err := url.Parse(strings.TrimSpace(raw))
+	if err != nil || u.Host == "" {
+		return raw
+	}
+	u.Scheme = strings.ToLower(u.Scheme)
+	host := strings.ToLower(u.Hostname())
+	if port := u.Port(); port != "" && !(u.Scheme == "http" && port == "80") && !(u.Scheme == "https" && port == "443") {
+		host += ":" + port
+	}
+	u.Host = host
+	u.Fragment, u.RawFragment, u.User = "", "", nil
+	if len(u.Path) > 1 {
+		u.Path = strings.TrimRight(u.Path, "/")
+		u.RawPath = ""
+	}
+
+	if twitterHosts[host] {
+		u.Scheme, u.Host = "https", "x.com"
+		u.Path = strings.ToLower(u.Path)
+		u.RawQuery = ""
+		return u.String()
+	}
+
+	q := u.Query()
+	for key := range q {
+		k := strings.ToLower(key)
+		if strings.HasPrefix(k, "utm_") || k == "fbclid" || k == "gclid" {
+			q.Del(key)
+		}
+	}
+	u.RawQuery = q.Encode()
+	return u.String()
+}
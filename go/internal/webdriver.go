@@ -2,7 +2,6 @@ package internal
 
 import (
 	"fmt"
-	"log"
 	"os"
 	"runtime"
 
@@ -10,51 +9,41 @@ import (
 	"github.com/tebeka/selenium/chrome"
 )
 
-var (
-	chromeDriverPath = "/usr/bin/chromedriver"
-	chromiumPath     = "/usr/bin/chromium"
-	port             = 9515
-	myOS             = runtime.GOOS
-)
-
-func init() {
-	log.SetFlags(log.LstdFlags | log.Lshortfile)
-	if myOS == "darwin" {
-		chromeDriverPath = "/opt/homebrew/bin/chromedriver"
-		chromiumPath = "/opt/homebrew/bin/chromium"
-		log.Println("🍏 macOS detected. Adjusting chromedriver path:", chromeDriverPath)
-	}
-}
+var myOS = runtime.GOOS
 
-func InitWebDriver() (selenium.WebDriver, func(), error) {
+// chromeCapabilities : WebDriver 세션 생성 시 사용할 크롬 옵션
+func chromeCapabilities(cfg *Config) selenium.Capabilities {
 	caps := selenium.Capabilities{"browserName": "chrome"}
 	chromeArgs := []string{
-		"--window-size=1280,1024",
+		fmt.Sprintf("--window-size=%d,%d", cfg.Browser.WindowWidth, cfg.Browser.WindowHeight),
 		"--disable-dev-shm-usage",
-		"--lang=ko-KR,ko",
-		"--user-agent=Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/121.0.0.0 Safari/537.36",
+		"--lang=" + cfg.Browser.Lang,
+		"--user-agent=" + cfg.Browser.UserAgent,
 	}
 	if myOS != "darwin" {
-		log.Println("🐧 Linux: running in headless mode.")
 		chromeArgs = append([]string{"--headless", "--disable-gpu", "--no-sandbox"}, chromeArgs...)
 	}
-	chromeCaps := chrome.Capabilities{
-		Path: chromiumPath,
+	caps.AddChrome(chrome.Capabilities{
+		Path: cfg.Selenium.ChromiumPath,
 		Args: chromeArgs,
-	}
-	caps.AddChrome(chromeCaps)
+	})
+	return caps
+}
 
+// startChromeDriverService : 로컬 ChromeDriver 프로세스를 띄운다.
+func startChromeDriverService(cfg *Config) (*selenium.Service, error) {
 	logFile, _ := os.Create("/tmp/chromedriver.log")
-	service, err := selenium.NewChromeDriverService(chromeDriverPath, port, selenium.Output(logFile))
+	service, err := selenium.NewChromeDriverService(cfg.Selenium.ChromeDriverPath, cfg.Selenium.Port, selenium.Output(logFile))
 	if err != nil {
-		return nil, nil, fmt.Errorf("failed to start ChromeDriver: %v", err)
+		return nil, fmt.Errorf("failed to start ChromeDriver: %v", err)
 	}
-	quitFunc := func() { service.Stop() }
+	return service, nil
+}
 
-	wd, err := selenium.NewRemote(caps, fmt.Sprintf("http://localhost:%d/wd/hub", port))
+func newWebDriver(cfg *Config, hubURL string) (selenium.WebDriver, error) {
+	wd, err := selenium.NewRemote(chromeCapabilities(cfg), hubURL)
 	if err != nil {
-		quitFunc()
-		return nil, nil, fmt.Errorf("failed to connect to WebDriver: %v", err)
+		return nil, fmt.Errorf("failed to connect to WebDriver: %v", err)
 	}
-	return wd, quitFunc, nil
+	return wd, nil
 }